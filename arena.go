@@ -0,0 +1,296 @@
+package polygol
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	slabMinChunk = 8
+	slabMaxChunk = 1024
+)
+
+// slab hands out zeroed values of T, allocated in chunks rather than one by
+// one. Chunks start small and double in size, so small operations stay
+// cheap. reset zeroes what was handed out so its chunks can be used again.
+type slab[T any] struct {
+	chunks [][]T
+	chunk  int // chunk of the next value
+	used   int // values used in that chunk
+	n      int // values handed out
+}
+
+func (s *slab[T]) alloc() *T {
+	if s.chunk == len(s.chunks) {
+		size := slabMinChunk
+		if len(s.chunks) > 0 {
+			size = 2 * len(s.chunks[len(s.chunks)-1])
+			if size > slabMaxChunk {
+				size = slabMaxChunk
+			}
+		}
+		s.chunks = append(s.chunks, make([]T, size))
+	}
+	c := s.chunks[s.chunk]
+	v := &c[s.used]
+	s.used++
+	s.n++
+	if s.used == len(c) {
+		s.chunk++
+		s.used = 0
+	}
+	return v
+}
+
+func (s *slab[T]) reset() {
+	var zero T
+	for i := 0; i <= s.chunk && i < len(s.chunks); i++ {
+		c := s.chunks[i]
+		if i == s.chunk {
+			c = c[:s.used]
+		}
+		for j := 0; j < len(c); j++ {
+			c[j] = zero
+		}
+	}
+	s.chunk, s.used, s.n = 0, 0, 0
+}
+
+// bytes is the size of the values handed out since the last reset.
+func (s *slab[T]) bytes() int64 {
+	var zero T
+	return int64(s.n) * int64(unsafe.Sizeof(zero))
+}
+
+// buffer hands out slices of T carved from shared chunks, for the small
+// slices hanging off the objects in an arena. Slices get a capped capacity,
+// so one that outgrows it is moved to the heap by append as usual.
+type buffer[T any] struct {
+	chunks [][]T
+	chunk  int // chunk of the next slice
+	used   int // values used in that chunk
+	n      int // values handed out
+}
+
+func (b *buffer[T]) make(length, capacity int) []T {
+	if capacity > slabMaxChunk {
+		return make([]T, length, capacity)
+	}
+	for b.chunk < len(b.chunks) && len(b.chunks[b.chunk])-b.used < capacity {
+		b.chunk++
+		b.used = 0
+	}
+	if b.chunk == len(b.chunks) {
+		size := slabMinChunk
+		if len(b.chunks) > 0 {
+			size = 2 * len(b.chunks[len(b.chunks)-1])
+			if size > slabMaxChunk {
+				size = slabMaxChunk
+			}
+		}
+		for size < capacity {
+			size *= 2
+		}
+		b.chunks = append(b.chunks, make([]T, size))
+	}
+	c := b.chunks[b.chunk]
+	v := c[b.used : b.used+length : b.used+capacity]
+	b.used += capacity
+	b.n += capacity
+	return v
+}
+
+func (b *buffer[T]) reset() {
+	var zero T
+	for i := 0; i <= b.chunk && i < len(b.chunks); i++ {
+		c := b.chunks[i]
+		if i == b.chunk {
+			c = c[:b.used]
+		}
+		for j := 0; j < len(c); j++ {
+			c[j] = zero
+		}
+	}
+	b.chunk, b.used, b.n = 0, 0, 0
+}
+
+// bytes is the size of the slices handed out since the last reset.
+func (b *buffer[T]) bytes() int64 {
+	var zero T
+	return int64(b.n) * int64(unsafe.Sizeof(zero))
+}
+
+// arena holds the objects an operation allocates most of while sweeping.
+// None of them are part of its output, so they can all be reused once the
+// operation is done.
+type arena struct {
+	points       slab[point]
+	events       slab[sweepEvent]
+	segments     slab[segment]
+	ringsOut     slab[ringOut]
+	eventNodes   slab[splayNode[*sweepEvent]]
+	segmentNodes slab[splayNode[*segment]]
+	coordNodes   slab[splayNode[float64]]
+	states       slab[state]
+
+	pointEvents buffer[*sweepEvent]
+	rings       buffer[*ringIn]
+	windings    buffer[int]
+	multiPolys  buffer[*multiPolyIn]
+	polys       buffer[*polyIn]
+
+	reported int64 // bytes counted against the memory limit
+}
+
+func (a *arena) newPoint(x, y float64) *point {
+	pt := a.points.alloc()
+	pt.x, pt.y = x, y
+	return pt
+}
+
+// newState makes a state with room for the given number of rings.
+func (a *arena) newState(numRings int) *state {
+	st := a.states.alloc()
+	st.rings = a.rings.make(0, numRings)
+	st.windings = a.windings.make(0, numRings)
+	st.multiPolys = a.multiPolys.make(0, numRings)
+	return st
+}
+
+// bytes estimates the memory used by the objects in the arena. Slices that
+// outgrew their buffers aren't counted.
+func (a *arena) bytes() int64 {
+	return a.points.bytes() + a.events.bytes() + a.segments.bytes() +
+		a.ringsOut.bytes() + a.eventNodes.bytes() + a.segmentNodes.bytes() +
+		a.coordNodes.bytes() + a.states.bytes() + a.pointEvents.bytes() +
+		a.rings.bytes() + a.windings.bytes() + a.multiPolys.bytes() +
+		a.polys.bytes()
+}
+
+func (a *arena) reset() {
+	a.points.reset()
+	a.events.reset()
+	a.segments.reset()
+	a.ringsOut.reset()
+	a.eventNodes.reset()
+	a.segmentNodes.reset()
+	a.coordNodes.reset()
+	a.states.reset()
+	a.pointEvents.reset()
+	a.rings.reset()
+	a.windings.reset()
+	a.multiPolys.reset()
+	a.polys.reset()
+	a.reported = 0
+}
+
+// arenaScope keeps track of the arenas used by an operation and by the group
+// and fallback operations it sets up, along with the memory they use.
+type arenaScope struct {
+	pool   *sync.Pool // nil unless pooling
+	mu     sync.Mutex
+	arenas []*arena
+	bytes  int64 // accessed atomically
+}
+
+func (sc *arenaScope) acquire() *arena {
+	var a *arena
+	if sc.pool != nil {
+		a = sc.pool.Get().(*arena)
+	} else {
+		a = &arena{}
+	}
+	sc.mu.Lock()
+	sc.arenas = append(sc.arenas, a)
+	sc.mu.Unlock()
+	return a
+}
+
+// release resets the arenas and puts them back in the pool. Nothing they
+// hold may be used afterwards.
+func (sc *arenaScope) release() {
+	if sc.pool == nil {
+		return
+	}
+	for i := 0; i < len(sc.arenas); i++ {
+		sc.arenas[i].reset()
+		sc.pool.Put(sc.arenas[i])
+	}
+	sc.arenas = nil
+}
+
+// mark returns the number of arenas acquired so far, for forget.
+func (sc *arenaScope) mark() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return len(sc.arenas)
+}
+
+// forget stops counting the memory of the arenas acquired since mark
+// against the limit. They're still only reset and pooled on release.
+func (sc *arenaScope) forget(mark int) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for i := mark; i < len(sc.arenas); i++ {
+		atomic.AddInt64(&sc.bytes, -sc.arenas[i].reported)
+		sc.arenas[i].reported = 0
+	}
+}
+
+func newArenaPool() *sync.Pool {
+	return &sync.Pool{New: func() interface{} { return &arena{} }}
+}
+
+// alloc returns the arena the operation allocates from.
+func (o *operation) alloc() *arena {
+	if o.arena == nil {
+		if o.scope == nil {
+			o.scope = &arenaScope{}
+		}
+		o.arena = o.scope.acquire()
+	}
+	return o.arena
+}
+
+// release gives the operation's arenas back once its output is built.
+func (o *operation) release() {
+	if o.scope != nil {
+		o.scope.release()
+	}
+}
+
+// memoryMark marks the arenas acquired so far in the operation's scope,
+// those of the operation itself included.
+func (o *operation) memoryMark() int {
+	o.alloc()
+	return o.scope.mark()
+}
+
+// forgetMemory stops counting the arenas acquired since mark against the
+// memory limit, once nothing in them is used anymore, such as those of a
+// failed sweep being retried.
+func (o *operation) forgetMemory(mark int) {
+	if o.scope != nil {
+		o.scope.forget(mark)
+	}
+}
+
+// checkMemory fails once the arenas of the operation, and of the operations
+// sweeping alongside it, use more memory than allowed.
+func (o *operation) checkMemory() error {
+	if o.opts.maxMemory <= 0 {
+		return nil
+	}
+	a := o.alloc()
+	used := a.bytes()
+	total := atomic.AddInt64(&o.scope.bytes, used-a.reported)
+	a.reported = used
+	if total > o.opts.maxMemory {
+		return fmt.Errorf(
+			`Operation uses too much memory (over %d bytes). Try increasing WithMaxMemory.`,
+			o.opts.maxMemory)
+	}
+	return nil
+}
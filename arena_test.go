@@ -0,0 +1,130 @@
+package polygol
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestSlab(t *testing.T) {
+	t.Parallel()
+
+	s := slab[point]{}
+	pts := []*point{}
+	for i := 0; i < 100; i++ {
+		pt := s.alloc()
+		pt.x = float64(i + 1)
+		pt.events = []*sweepEvent{{}}
+		pts = append(pts, pt)
+	}
+	expect(t, len(s.chunks) == 4) // 8 + 16 + 32 + 64
+	expect(t, pts[0].x == 1)
+	expect(t, pts[99].x == 100)
+	expect(t, s.bytes() == 100*int64(unsafe.Sizeof(point{})))
+
+	s.reset()
+	expect(t, s.bytes() == 0)
+	for i := 0; i < len(pts); i++ {
+		expect(t, pts[i].x == 0 && pts[i].events == nil)
+	}
+
+	// chunks are handed out again rather than allocated
+	expect(t, s.alloc() == pts[0])
+	expect(t, len(s.chunks) == 4)
+}
+
+func TestPooling(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{
+		{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}},
+		{{{10, 0}, {14, 0}, {14, 4}, {10, 4}, {10, 0}}},
+	}
+	b := Geom{
+		{{{2, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 2}}},
+		{{{12, 2}, {13, 3}, {12, 5}, {12, 2}}},
+	}
+	want, err := Union(a, b)
+	terr(t, err)
+
+	t.Run("repeated", func(t *testing.T) {
+		p := New(WithPooling())
+		for i := 0; i < 10; i++ {
+			got, err := p.Union(a, b)
+			terr(t, err)
+			expect(t, reflect.DeepEqual(got, want))
+		}
+		result, err := p.Run(OpUnion, a, b)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result.Geom, want))
+		tree, err := p.Tree(OpUnion, a, b)
+		terr(t, err)
+		expect(t, len(tree.Children) == 2)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		p := New(WithPooling(), WithGroupWorkers(2))
+		wg := sync.WaitGroup{}
+		results := make([]Geom, 8)
+		for i := 0; i < len(results); i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = p.Union(a, b)
+			}(i)
+		}
+		wg.Wait()
+		for i := 0; i < len(results); i++ {
+			expect(t, reflect.DeepEqual(results[i], want))
+		}
+	})
+}
+
+func TestMaxMemory(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}}
+	b := Geom{{{{2, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 2}}}}
+
+	_, err := New(WithMaxMemory(100)).Union(a, b)
+	expect(t, err != nil)
+
+	_, err = New(WithMaxMemory(1<<20)).Union(a, b)
+	terr(t, err)
+
+	_, err = New(WithMaxMemory(100), WithPooling()).Union(a, b)
+	expect(t, err != nil)
+	_, err = New(WithMaxMemory(1<<20), WithPooling()).Union(a, b)
+	terr(t, err)
+}
+
+func TestMaxMemoryForgets(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}}
+	b := Geom{{{{2, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 2}}}}
+
+	o := New(WithMaxMemory(1 << 20)).newOperation("union")
+	_, _, err := o.sweepOnce(a, b)
+	terr(t, err)
+	used := o.scope.bytes
+
+	t.Run("failed-sweep", func(t *testing.T) {
+		t.Parallel()
+		o := New(WithFallback(), WithMaxMemory(used+used/2)).newOperation("union")
+		_, _, err := o.sweepOnce(a, b)
+		terr(t, err)
+		_, _, err = o.sweepFallback(newAlgorithmError("Unable to pop()"), a, []Geom{b})
+		terr(t, err)
+		expect(t, o.scope.bytes == used)
+	})
+
+	t.Run("noder", func(t *testing.T) {
+		t.Parallel()
+		o := New(WithPrecision(1), WithMaxMemory(1<<20)).newOperation("union")
+		_, _, err := o.sweepOnce(a, b)
+		terr(t, err)
+		expect(t, o.scope.bytes <= used)
+	})
+}
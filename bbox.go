@@ -13,12 +13,21 @@ func (b bbox) isInBbox(point point) bool {
 }
 
 func (b bbox) getBboxOverlap(ob bbox) *bbox {
+	overlap, ok := b.overlap(ob)
+	if !ok {
+		return nil
+	}
+	return &overlap
+}
+
+// overlap is getBboxOverlap without the allocation, for the sweep.
+func (b bbox) overlap(ob bbox) (bbox, bool) {
 	// check if the bboxes overlap at all
 	if ob.ur.x < b.ll.x ||
 		b.ur.x < ob.ll.x ||
 		ob.ur.y < b.ll.y ||
 		b.ur.y < ob.ll.y {
-		return nil
+		return bbox{}, false
 	}
 
 	// find the middle two X values
@@ -41,8 +50,8 @@ func (b bbox) getBboxOverlap(ob bbox) *bbox {
 		upperY = b.ur.y
 	}
 
-	return &bbox{
+	return bbox{
 		ll: point{x: lowerX, y: lowerY},
 		ur: point{x: upperX, y: upperY},
-	}
+	}, true
 }
@@ -5,56 +5,139 @@ import (
 )
 
 func BenchmarkAsiaUnion(b *testing.B) {
-	geoms, err := loadGeoms("test/end-to-end/countries-asia/args.geojson")
+	geoms, err := loadGeoms("testdata/end-to-end/countries-asia/args.geojson")
 	if err != nil {
 		b.Fatal(err)
 	}
-	_, err = Union(Geom{}, geoms...)
-	if err != nil {
-		b.Fatal(err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = Union(Geom{}, geoms...)
+		if err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
 func BenchmarkAfricaUnion(b *testing.B) {
-	geoms, err := loadGeoms("test/end-to-end/countries-africa/args.geojson")
+	geoms, err := loadGeoms("testdata/end-to-end/countries-africa/args.geojson")
 	if err != nil {
 		b.Fatal(err)
 	}
-	_, err = Union(Geom{}, geoms...)
-	if err != nil {
-		b.Fatal(err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = Union(Geom{}, geoms...)
+		if err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
 func BenchmarkEuropeUnion(b *testing.B) {
-	geoms, err := loadGeoms("test/end-to-end/countries-europe/args.geojson")
+	geoms, err := loadGeoms("testdata/end-to-end/countries-europe/args.geojson")
 	if err != nil {
 		b.Fatal(err)
 	}
-	_, err = Union(Geom{}, geoms...)
-	if err != nil {
-		b.Fatal(err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = Union(Geom{}, geoms...)
+		if err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
 func BenchmarkNorthAmericaUnion(b *testing.B) {
-	geoms, err := loadGeoms("test/end-to-end/countries-north-america/args.geojson")
+	geoms, err := loadGeoms("testdata/end-to-end/countries-north-america/args.geojson")
 	if err != nil {
 		b.Fatal(err)
 	}
-	_, err = Union(Geom{}, geoms...)
-	if err != nil {
-		b.Fatal(err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = Union(Geom{}, geoms...)
+		if err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
 func BenchmarkSouthAmericaUnion(b *testing.B) {
-	geoms, err := loadGeoms("test/end-to-end/countries-south-america/args.geojson")
+	geoms, err := loadGeoms("testdata/end-to-end/countries-south-america/args.geojson")
 	if err != nil {
 		b.Fatal(err)
 	}
-	_, err = Union(Geom{}, geoms...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = Union(Geom{}, geoms...)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAsiaUnionAll(b *testing.B) {
+	geoms, err := loadGeoms("testdata/end-to-end/countries-asia/args.geojson")
 	if err != nil {
 		b.Fatal(err)
 	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = UnionAll(geoms, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestCountriesAllocs catches allocation regressions in the hot path. The
+// budgets are a little over what a union of each fixture takes now, lower
+// them when allocations are cut.
+func TestCountriesAllocs(t *testing.T) {
+	// not parallel, allocations are counted across the whole program
+	if testing.Short() {
+		t.Skip("skipping allocation budgets in short mode")
+	}
+
+	budgets := []struct {
+		name   string
+		allocs float64
+	}{
+		{"countries-africa", 44000},
+		{"countries-asia", 80000},
+		{"countries-europe", 71000},
+		{"countries-north-america", 70000},
+		{"countries-south-america", 27500},
+	}
+	for _, budget := range budgets {
+		geoms, err := loadGeoms("testdata/end-to-end/" + budget.name + "/args.geojson")
+		terr(t, err)
+		allocs := testing.AllocsPerRun(1, func() {
+			_, err = Union(Geom{}, geoms...)
+		})
+		terr(t, err)
+		if allocs > budget.allocs {
+			t.Errorf("%s: %.0f allocations, over the budget of %.0f", budget.name, allocs, budget.allocs)
+		}
+	}
+}
+
+func BenchmarkSmallIntersection(b *testing.B) {
+	subject := Geom{{{{0, 0}, {4, 0}, {5, 2}, {4, 4}, {0, 4}, {-1, 2}, {0, 0}}}}
+	clip := Geom{{{{2, 1}, {6, 1}, {6, 3}, {2, 3}, {2, 1}}}}
+	run := func(b *testing.B, p *Polygol) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := p.Intersection(subject, clip)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Run("default", func(b *testing.B) { run(b, New()) })
+	b.Run("pooling", func(b *testing.B) { run(b, New(WithPooling())) })
 }
@@ -0,0 +1,206 @@
+package polygol
+
+import (
+	"math"
+	"sort"
+)
+
+// ClipRect intersects geom with the rectangle from (minX, minY) to
+// (maxX, maxY). The result is topologically equal to that of Intersection
+// with the rectangle as a polygon, though where edges cross the rectangle
+// the two may differ in the last bits of a coordinate. Rings are clipped
+// against the rectangle in linear time (Sutherland-Hodgman), so the
+// rectangle never enters the sweep and parts of geom outside of it are
+// discarded up front. Clipped polygons that don't interact with each other
+// go straight to the result, as with WithPassThrough, so geom is assumed to
+// be valid. Only when clipping leaves rings doubling back along the
+// rectangle's edges, or cuts a hole open, does the sweep have to sort them
+// out.
+func (p *Polygol) ClipRect(geom Geom, minX, minY, maxX, maxY float64) (Geom, error) {
+	if minX > maxX || minY > maxY {
+		return p.Union(Geom{})
+	}
+	rect := bbox{ll: point{x: minX, y: minY}, ur: point{x: maxX, y: maxY}}
+
+	// snapping may move clipped rings into each other
+	clean := p.opts.precision == 0 && p.opts.snapDistance == 0
+	clipped := make(Geom, 0, len(geom))
+	for i := 0; i < len(geom); i++ {
+		if len(geom[i]) == 0 {
+			continue
+		}
+		exteriorRing := clipRing(geom[i][0], rect)
+		if exteriorRing == nil {
+			continue
+		}
+		clean = clean && isCleanClip(exteriorRing, rect)
+		poly := [][][]float64{exteriorRing}
+		for j := 1; j < len(geom[i]); j++ {
+			if ring := clipRing(geom[i][j], rect); ring != nil {
+				// a hole reaching the rectangle's edges opens up the polygon
+				clean = clean && isWithinRect(ring, rect)
+				poly = append(poly, ring)
+			}
+		}
+		clipped = append(clipped, poly)
+	}
+	if !clean {
+		return p.Union(clipped)
+	}
+	o := p.newOperation("union")
+	o.opts.passThrough = true
+	return o.run(clipped)
+}
+
+func ClipRect(geom Geom, minX, minY, maxX, maxY float64) (Geom, error) {
+	return New().ClipRect(geom, minX, minY, maxX, maxY)
+}
+
+// clipRing clips a ring to the rectangle, returning nil if nothing of it is
+// left. Rings within the rectangle are returned as they are.
+func clipRing(ring [][]float64, rect bbox) [][]float64 {
+	positions := ring
+	if n := len(positions); n > 1 && comparePositions(positions[0], positions[n-1]) == 0 {
+		positions = positions[:n-1]
+	}
+
+	ringBbox := bbox{
+		ll: point{x: math.Inf(1), y: math.Inf(1)},
+		ur: point{x: math.Inf(-1), y: math.Inf(-1)},
+	}
+	for i := 0; i < len(positions); i++ {
+		if len(positions[i]) < 2 {
+			// leave it to the sweep to complain
+			return ring
+		}
+		ringBbox.ll.x = math.Min(ringBbox.ll.x, positions[i][0])
+		ringBbox.ll.y = math.Min(ringBbox.ll.y, positions[i][1])
+		ringBbox.ur.x = math.Max(ringBbox.ur.x, positions[i][0])
+		ringBbox.ur.y = math.Max(ringBbox.ur.y, positions[i][1])
+	}
+	if len(positions) < 3 || rect.getBboxOverlap(ringBbox) == nil {
+		return nil
+	}
+	if rect.isInBbox(ringBbox.ll) && rect.isInBbox(ringBbox.ur) {
+		return ring
+	}
+
+	positions = clipRingEdge(positions, 0, rect.ll.x, true)
+	positions = clipRingEdge(positions, 0, rect.ur.x, false)
+	positions = clipRingEdge(positions, 1, rect.ll.y, true)
+	positions = clipRingEdge(positions, 1, rect.ur.y, false)
+	if len(positions) < 3 {
+		return nil
+	}
+	return append(positions, positions[0])
+}
+
+// isCleanClip reports whether a clipped ring is as valid as the ring it was
+// clipped from. Sutherland-Hodgman joins the pieces of a ring that leaves
+// and re-enters the rectangle along its edges, and where those joins
+// overlap, or leave nothing but edges, the ring is degenerate.
+func isCleanClip(ring [][]float64, rect bbox) bool {
+	if len(ring) < 2 || len(ring[0]) < 2 {
+		return false
+	}
+	area := 0.0
+	sides := [4][][2]float64{}
+	for i := 0; i < len(ring)-1; i++ {
+		a, b := ring[i], ring[i+1]
+		if len(b) < 2 {
+			return false
+		}
+		area += a[0]*b[1] - b[0]*a[1]
+		switch {
+		case a[0] == rect.ll.x && b[0] == rect.ll.x:
+			sides[0] = append(sides[0], [2]float64{math.Min(a[1], b[1]), math.Max(a[1], b[1])})
+		case a[0] == rect.ur.x && b[0] == rect.ur.x:
+			sides[1] = append(sides[1], [2]float64{math.Min(a[1], b[1]), math.Max(a[1], b[1])})
+		case a[1] == rect.ll.y && b[1] == rect.ll.y:
+			sides[2] = append(sides[2], [2]float64{math.Min(a[0], b[0]), math.Max(a[0], b[0])})
+		case a[1] == rect.ur.y && b[1] == rect.ur.y:
+			sides[3] = append(sides[3], [2]float64{math.Min(a[0], b[0]), math.Max(a[0], b[0])})
+		}
+	}
+	if area == 0 {
+		return false
+	}
+	for i := 0; i < len(sides); i++ {
+		edges := sides[i]
+		sort.Slice(edges, func(a, b int) bool { return edges[a][0] < edges[b][0] })
+		for j := 1; j < len(edges); j++ {
+			if edges[j][0] < edges[j-1][1] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isWithinRect reports whether a ring lies within the rectangle without
+// touching its edges.
+func isWithinRect(ring [][]float64, rect bbox) bool {
+	for i := 0; i < len(ring); i++ {
+		if len(ring[i]) < 2 ||
+			ring[i][0] <= rect.ll.x || ring[i][0] >= rect.ur.x ||
+			ring[i][1] <= rect.ll.y || ring[i][1] >= rect.ur.y {
+			return false
+		}
+	}
+	return true
+}
+
+// clipRingEdge is one step of Sutherland-Hodgman: it keeps the part of the
+// ring on one side of the line where the given axis equals value, above it
+// if keepAbove, else below it.
+func clipRingEdge(positions [][]float64, axis int, value float64, keepAbove bool) [][]float64 {
+	if len(positions) == 0 {
+		return positions
+	}
+	inside := func(position []float64) bool {
+		if keepAbove {
+			return position[axis] >= value
+		}
+		return position[axis] <= value
+	}
+	out := make([][]float64, 0, len(positions)+4)
+	prev := positions[len(positions)-1]
+	prevInside := inside(prev)
+	for i := 0; i < len(positions); i++ {
+		cur := positions[i]
+		curInside := inside(cur)
+		if curInside != prevInside {
+			out = append(out, crossingPosition(prev, cur, axis, value))
+		}
+		if curInside {
+			out = append(out, cur)
+		}
+		prev, prevInside = cur, curInside
+	}
+	return out
+}
+
+// crossingPosition finds where the edge between two positions crosses the
+// line where the given axis equals value, the same way the sweep finds
+// intersections with a vertical or horizontal segment. Any Z/M values are
+// interpolated.
+func crossingPosition(a, b []float64, axis int, value float64) []float64 {
+	// work from the left endpoint, as the sweep does
+	left, right := a, b
+	if comparePositions(b[:2], a[:2]) < 0 {
+		left, right = b, a
+	}
+	v := positionVector(right).sub(positionVector(left))
+	var crossing vector
+	if axis == 0 {
+		crossing, _ = verticalIntersection(v, positionVector(left), value)
+	} else {
+		crossing, _ = horizontalIntersection(v, positionVector(left), value)
+	}
+	position := []float64{crossing.x, crossing.y}
+	t := (value - left[axis]) / (right[axis] - left[axis])
+	for k := 2; k < len(left) && k < len(right); k++ {
+		position = append(position, left[k]+t*(right[k]-left[k]))
+	}
+	return position
+}
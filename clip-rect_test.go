@@ -0,0 +1,149 @@
+package polygol
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestClipRing(t *testing.T) {
+	t.Parallel()
+
+	rect := bbox{ll: point{x: 0, y: 0}, ur: point{x: 10, y: 10}}
+
+	// inside
+	ring := [][]float64{{1, 1}, {2, 1}, {2, 2}, {1, 1}}
+	expect(t, reflect.DeepEqual(clipRing(ring, rect), ring))
+
+	// outside
+	expect(t, clipRing([][]float64{{11, 1}, {12, 1}, {12, 2}, {11, 1}}, rect) == nil)
+
+	// crossing, with Z interpolated
+	clipped := clipRing([][]float64{{-10, 5, 0}, {10, 5, 20}, {10, 8, 20}, {-10, 5, 0}}, rect)
+	expect(t, len(clipped) == 5)
+	expect(t, equalVector(clipped[0], []float64{0, 6.5, 10}))
+	expect(t, equalVector(clipped[1], []float64{0, 5, 10}))
+	expect(t, equalVector(clipped[2], []float64{10, 5, 20}))
+	expect(t, equalVector(clipped[3], []float64{10, 8, 20}))
+	expect(t, equalVector(clipped[4], clipped[0]))
+}
+
+func TestIsCleanClip(t *testing.T) {
+	t.Parallel()
+
+	uShape := [][]float64{{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 2}, {2, 2}, {2, 6}, {0, 6}, {0, 0}}
+
+	// the bottom of a u-shape is cut off in one piece
+	rect := bbox{ll: point{x: 0, y: 0}, ur: point{x: 10, y: 4}}
+	expect(t, isCleanClip(clipRing(uShape, rect), rect))
+
+	// its arms are joined along the rectangle's bottom edge
+	rect = bbox{ll: point{x: 0, y: 3}, ur: point{x: 10, y: 10}}
+	expect(t, !isCleanClip(clipRing(uShape, rect), rect))
+
+	// nothing but an edge is left
+	expect(t, !isCleanClip([][]float64{{0, 3}, {1, 3}, {2, 3}, {0, 3}}, rect))
+
+	expect(t, isWithinRect([][]float64{{1, 4}, {2, 4}, {2, 5}, {1, 4}}, rect))
+	expect(t, !isWithinRect([][]float64{{0, 4}, {2, 4}, {2, 5}, {0, 4}}, rect))
+}
+
+func TestClipRect(t *testing.T) {
+	t.Parallel()
+
+	rectGeom := func(minX, minY, maxX, maxY float64) Geom {
+		return Geom{{{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY}}}}
+	}
+
+	testCases := []struct {
+		name    string
+		geom    Geom
+		numPoly int
+	}{
+		{
+			name: "u-shape-split-in-two",
+			geom: Geom{{{{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 2}, {2, 2}, {2, 6}, {0, 6}, {0, 0}}}},
+			// the rectangle only takes the arms
+			numPoly: 2,
+		},
+		{
+			name: "hole",
+			geom: Geom{{
+				{{-1, -1}, {7, -1}, {7, 7}, {-1, 7}, {-1, -1}},
+				{{1, 3.5}, {5, 3.5}, {5, 5}, {1, 5}, {1, 3.5}},
+			}},
+			numPoly: 1,
+		},
+		{
+			name: "hole-crossing-edge",
+			geom: Geom{{
+				{{-1, -1}, {7, -1}, {7, 7}, {-1, 7}, {-1, -1}},
+				{{1, 1}, {5, 1}, {5, 5}, {1, 5}, {1, 1}},
+			}},
+			// the hole becomes a notch in the exterior ring
+			numPoly: 1,
+		},
+		{
+			name:    "outside",
+			geom:    rectGeom(10, 10, 11, 11),
+			numPoly: 0,
+		},
+		{
+			name:    "inside",
+			geom:    rectGeom(1, 3.5, 2, 4),
+			numPoly: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ClipRect(tc.geom, -1, 3, 7, 8)
+			terr(t, err)
+			expect(t, len(result) == tc.numPoly)
+			expected, err := Intersection(tc.geom, rectGeom(-1, 3, 7, 8))
+			terr(t, err)
+			expect(t, reflect.DeepEqual(result, expected))
+			for i := 0; i < len(result); i++ {
+				expect(t, len(result[i]) == len(expected[i]))
+			}
+		})
+	}
+
+	t.Run("country-fixtures", func(t *testing.T) {
+		t.Parallel()
+		geoms, err := loadGeoms("testdata/end-to-end/countries-europe/args.geojson")
+		terr(t, err)
+		// rectangles cutting across borders and coastlines at all angles
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 40; i++ {
+			geom := geoms[rng.Intn(len(geoms))]
+			box := bbox{ll: point{x: math.Inf(1), y: math.Inf(1)}, ur: point{x: math.Inf(-1), y: math.Inf(-1)}}
+			for _, poly := range geom {
+				for _, pos := range poly[0] {
+					box.ll.x, box.ll.y = math.Min(box.ll.x, pos[0]), math.Min(box.ll.y, pos[1])
+					box.ur.x, box.ur.y = math.Max(box.ur.x, pos[0]), math.Max(box.ur.y, pos[1])
+				}
+			}
+			w, h := box.ur.x-box.ll.x, box.ur.y-box.ll.y
+			minX, minY := box.ll.x+rng.Float64()*w, box.ll.y+rng.Float64()*h
+			maxX, maxY := minX+rng.Float64()*w/2, minY+rng.Float64()*h/2
+			result, err := ClipRect(geom, minX, minY, maxX, maxY)
+			terr(t, err)
+			expected, err := Intersection(geom, rectGeom(minX, minY, maxX, maxY))
+			terr(t, err)
+			equal, err := EqualTopo(result, expected, 1e-9)
+			terr(t, err)
+			expect(t, equal)
+		}
+	})
+
+	t.Run("empty-rectangle", func(t *testing.T) {
+		t.Parallel()
+		result, err := ClipRect(rectGeom(0, 0, 1, 1), 1, 1, 0, 0)
+		terr(t, err)
+		expect(t, len(result) == 0)
+	})
+}
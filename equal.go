@@ -0,0 +1,206 @@
+package polygol
+
+import (
+	"math"
+	"sort"
+)
+
+// EqualTopo reports whether a and b cover the same area, regardless of
+// vertex order, ring start or redundant colinear points. Boundaries that
+// differ by no more than tol are considered equal; with a tol of zero the
+// areas must match exactly.
+func (p *Polygol) EqualTopo(a, b Geom, tol float64) (bool, error) {
+	o := p.newOperation("xor")
+	defer o.release()
+	// every segment is needed, none may be passed through
+	o.opts.passThrough = false
+	segments, _, err := o.sweepSegments(a, b)
+	if err != nil {
+		return false, err
+	}
+
+	// Every segment in the XOR result is a piece of boundary the two
+	// geoms don't share.
+	diff := []*segment{}
+	area, length := 0.0, 0.0
+	for i := 0; i < len(segments); i++ {
+		seg := segments[i]
+		if !seg.isInResult() {
+			continue
+		}
+		diff = append(diff, seg)
+		lp, rp := seg.leftSE.point, seg.rightSE.point
+		cross := lp.x*rp.y - rp.x*lp.y
+		// bottom edges of the difference run left to right when going
+		// counter-clockwise, top edges the other way
+		if len(seg.afterState().multiPolys)%2 == 1 {
+			area += cross
+		} else {
+			area -= cross
+		}
+		length += math.Hypot(rp.x-lp.x, rp.y-lp.y)
+	}
+	if len(diff) == 0 {
+		return true, nil
+	}
+	if tol <= 0 {
+		return false, nil
+	}
+	// Geoms within tol of each other differ by slivers no wider than tol,
+	// whose area is at most about tol times half their perimeter.
+	if math.Abs(area)/2 > tol*length {
+		return false, nil
+	}
+
+	// Pair each piece with the edges of the other geom that come within
+	// tol of it, then check the pieces one by one.
+	edges := geomEdges(a)
+	numEdgesA := len(edges)
+	edges = append(edges, geomEdges(b)...)
+	bboxes := make([]bbox, 0, len(diff)+len(edges))
+	for i := 0; i < len(diff); i++ {
+		bboxes = append(bboxes, diff[i].bbox())
+	}
+	for i := 0; i < len(edges); i++ {
+		bboxes = append(bboxes, bbox{
+			ll: point{x: math.Min(edges[i][0].x, edges[i][1].x), y: math.Min(edges[i][0].y, edges[i][1].y)},
+			ur: point{x: math.Max(edges[i][0].x, edges[i][1].x), y: math.Max(edges[i][0].y, edges[i][1].y)},
+		})
+	}
+	nearEdges := make([][][2]vector, len(diff))
+	forEachOverlap(bboxes, tol/2, func(i, j int) {
+		if i >= len(diff) || j < len(diff) {
+			return
+		}
+		k := j - len(diff)
+		if diff[i].rings[0].poly.multiPoly.isSubject == (k >= numEdgesA) {
+			nearEdges[i] = append(nearEdges[i], edges[k])
+		}
+	})
+	for i := 0; i < len(diff); i++ {
+		if !isWithinTolerance(diff[i].leftSE.point.xy(), diff[i].rightSE.point.xy(), nearEdges[i], tol) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func EqualTopo(a, b Geom, tol float64) (bool, error) {
+	return New().EqualTopo(a, b, tol)
+}
+
+// geomEdges lists the edges of every ring in geom as pairs of points.
+func geomEdges(geom Geom) [][2]vector {
+	edges := [][2]vector{}
+	for i := 0; i < len(geom); i++ {
+		for j := 0; j < len(geom[i]); j++ {
+			ring := geom[i][j]
+			for k := 0; k < len(ring); k++ {
+				next := ring[(k+1)%len(ring)]
+				if len(ring[k]) < 2 || len(next) < 2 {
+					continue
+				}
+				edges = append(edges, [2]vector{positionVector(ring[k]), positionVector(next)})
+			}
+		}
+	}
+	return edges
+}
+
+// isWithinTolerance reports whether every point of the segment from pt1 to
+// pt2 lies within tol of at least one of the edges.
+func isWithinTolerance(pt1, pt2 vector, edges [][2]vector, tol float64) bool {
+	intervals := [][]float64{}
+	for i := 0; i < len(edges); i++ {
+		edge := edges[i]
+		if math.Max(pt1.x, pt2.x)+tol < math.Min(edge[0].x, edge[1].x) ||
+			math.Min(pt1.x, pt2.x)-tol > math.Max(edge[0].x, edge[1].x) ||
+			math.Max(pt1.y, pt2.y)+tol < math.Min(edge[0].y, edge[1].y) ||
+			math.Min(pt1.y, pt2.y)-tol > math.Max(edge[0].y, edge[1].y) {
+			continue
+		}
+		interval := toleranceInterval(pt1, pt2, edge[0], edge[1], tol)
+		if interval != nil {
+			intervals = append(intervals, interval)
+		}
+	}
+
+	// check the intervals cover the whole segment
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i][0] < intervals[j][0]
+	})
+	reach := 0.0
+	for i := 0; i < len(intervals); i++ {
+		if intervals[i][0] > reach {
+			return false
+		}
+		if intervals[i][1] > reach {
+			reach = intervals[i][1]
+		}
+	}
+	return reach >= 1
+}
+
+// toleranceInterval finds the range of parameters t along the segment from
+// pt1 to pt2 whose points lie within tol of the edge from edgeStart to
+// edgeEnd, or nil if there are none. Those points make up a stadium: a
+// disk around either end of the edge and the band between them. That's
+// convex, so the range spans those within each of the three.
+func toleranceInterval(pt1, pt2, edgeStart, edgeEnd vector, tol float64) []float64 {
+	d := pt2.sub(pt1)
+	t0, t1 := math.Inf(1), math.Inf(-1)
+	span := func(lo, hi float64) {
+		lo, hi = math.Max(lo, 0), math.Min(hi, 1)
+		if lo <= hi {
+			t0, t1 = math.Min(t0, lo), math.Max(t1, hi)
+		}
+	}
+
+	for _, center := range []vector{edgeStart, edgeEnd} {
+		// |pt1 + t*d - center|^2 <= tol^2
+		w := pt1.sub(center)
+		a, b, c := dotProduct(d, d), 2*dotProduct(d, w), dotProduct(w, w)-tol*tol
+		if a == 0 {
+			if c <= 0 {
+				span(0, 1)
+			}
+			continue
+		}
+		if disc := b*b - 4*a*c; disc >= 0 {
+			sq := math.Sqrt(disc)
+			span((-b-sq)/(2*a), (-b+sq)/(2*a))
+		}
+	}
+
+	e := edgeEnd.sub(edgeStart)
+	if lenSq := dotProduct(e, e); lenSq > 0 {
+		// projected between the ends of the edge, and no further than tol
+		// to either side of it
+		w := pt1.sub(edgeStart)
+		lo, hi := linearInterval(dotProduct(w, e)/lenSq, dotProduct(d, e)/lenSq, 0, 1)
+		length := math.Sqrt(lenSq)
+		lo2, hi2 := linearInterval(crossProduct(e, w)/length, crossProduct(e, d)/length, -tol, tol)
+		span(math.Max(lo, lo2), math.Min(hi, hi2))
+	}
+
+	if t0 > t1 {
+		return nil
+	}
+	return []float64{t0, t1}
+}
+
+// linearInterval finds the range of t for which f0 + t*f1 lies between lo
+// and hi, which is empty if it comes back the wrong way around.
+func linearInterval(f0, f1, lo, hi float64) (float64, float64) {
+	if f1 == 0 {
+		if lo <= f0 && f0 <= hi {
+			return math.Inf(-1), math.Inf(1)
+		}
+		return math.Inf(1), math.Inf(-1)
+	}
+	t0, t1 := (lo-f0)/f1, (hi-f0)/f1
+	if t0 > t1 {
+		t0, t1 = t1, t0
+	}
+	return t0, t1
+}
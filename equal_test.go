@@ -0,0 +1,117 @@
+package polygol
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEqualTopo(t *testing.T) {
+	t.Parallel()
+
+	square := Geom{{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}}
+
+	t.Run("reordered", func(t *testing.T) {
+		// clockwise, different start, redundant colinear point
+		other := Geom{{{{10, 10}, {10, 0}, {5, 0}, {0, 0}, {0, 10}, {10, 10}}}}
+		equal, err := EqualTopo(square, other, 0)
+		terr(t, err)
+		expect(t, equal)
+	})
+
+	t.Run("split-in-two", func(t *testing.T) {
+		other := Geom{
+			{{{0, 0}, {5, 0}, {5, 10}, {0, 10}, {0, 0}}},
+			{{{5, 0}, {10, 0}, {10, 10}, {5, 10}, {5, 0}}},
+		}
+		equal, err := EqualTopo(square, other, 0)
+		terr(t, err)
+		expect(t, equal)
+	})
+
+	t.Run("different", func(t *testing.T) {
+		other := Geom{{{{0, 0}, {11, 0}, {11, 10}, {0, 10}, {0, 0}}}}
+		equal, err := EqualTopo(square, other, 0)
+		terr(t, err)
+		expect(t, !equal)
+		equal, err = EqualTopo(square, other, 0.5)
+		terr(t, err)
+		expect(t, !equal)
+		equal, err = EqualTopo(square, other, 1.5)
+		terr(t, err)
+		expect(t, equal)
+	})
+
+	t.Run("within-tolerance", func(t *testing.T) {
+		other := Geom{{{{0, 0}, {10, 0}, {10.000001, 5}, {10, 10}, {0, 10}, {0, 0}}}}
+		equal, err := EqualTopo(square, other, 0)
+		terr(t, err)
+		expect(t, !equal)
+		equal, err = EqualTopo(square, other, 1e-5)
+		terr(t, err)
+		expect(t, equal)
+		equal, err = EqualTopo(square, other, 1e-7)
+		terr(t, err)
+		expect(t, !equal)
+	})
+
+	t.Run("hole", func(t *testing.T) {
+		other := Geom{{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			{{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}},
+		}}
+		equal, err := EqualTopo(square, other, 1)
+		terr(t, err)
+		expect(t, !equal)
+	})
+
+	t.Run("island-filling-a-hole", func(t *testing.T) {
+		// every boundary is close to the other geom's, but the island
+		// covers what the other geom leaves open
+		geom := Geom{
+			{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{3, 3}, {3, 7}, {7, 7}, {7, 3}, {3, 3}},
+			},
+			{{{3.0005, 3.0005}, {6.9995, 3.0005}, {6.9995, 6.9995}, {3.0005, 6.9995}, {3.0005, 3.0005}}},
+		}
+		other := Geom{{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			{{3.0005, 3.0005}, {3.0005, 6.9995}, {6.9995, 6.9995}, {6.9995, 3.0005}, {3.0005, 3.0005}},
+		}}
+		equal, err := EqualTopo(geom, other, 1e-3)
+		terr(t, err)
+		expect(t, !equal)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		equal, err := EqualTopo(Geom{}, Geom{}, 0)
+		terr(t, err)
+		expect(t, equal)
+		equal, err = EqualTopo(square, Geom{}, 0)
+		terr(t, err)
+		expect(t, !equal)
+	})
+}
+
+func TestToleranceInterval(t *testing.T) {
+	t.Parallel()
+
+	near := func(got []float64, t0, t1 float64) bool {
+		return len(got) == 2 && math.Abs(got[0]-t0) < 1e-12 && math.Abs(got[1]-t1) < 1e-12
+	}
+
+	edge := [2]vector{{2, 0}, {5, 0}}
+	// past the ends of the edge, within the disks around them
+	reach := math.Sqrt(1.5*1.5 - 1)
+	expect(t, near(toleranceInterval(vector{0, 1}, vector{10, 1}, edge[0], edge[1], 1.5), (2-reach)/10, (5+reach)/10))
+	// across the band
+	expect(t, near(toleranceInterval(vector{3, -5}, vector{3, 5}, edge[0], edge[1], 1), 0.4, 0.6))
+	// all of it
+	expect(t, near(toleranceInterval(vector{2.5, 0.5}, vector{4.5, -0.5}, edge[0], edge[1], 1), 0, 1))
+	expect(t, near(toleranceInterval(vector{3, 0.5}, vector{3, 0.5}, edge[0], edge[1], 1), 0, 1))
+	// none of it
+	expect(t, toleranceInterval(vector{0, 2}, vector{10, 2}, edge[0], edge[1], 1) == nil)
+	expect(t, toleranceInterval(vector{7, -1}, vector{9, 1}, edge[0], edge[1], 1) == nil)
+	// a degenerate edge is a disk
+	expect(t, near(toleranceInterval(vector{0, 0}, vector{4, 0}, vector{2, 0}, vector{2, 0}, 1), 0.25, 0.75))
+}
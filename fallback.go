@@ -0,0 +1,124 @@
+package polygol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Strategy names the way an operation was carried out.
+type Strategy int
+
+const (
+	// StrategyDefault runs the operation as configured.
+	StrategyDefault Strategy = iota
+	// StrategyRobust decides every side-of-line and angle question with
+	// exact predicates, as WithRobustPredicates does.
+	StrategyRobust
+	// StrategySnapRound uses exact predicates and snap rounds the inputs
+	// to a grid coarser than the configured precision, or than the
+	// floating point noise of the input coordinates if none is set.
+	StrategySnapRound
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case StrategyDefault:
+		return "default"
+	case StrategyRobust:
+		return "robust"
+	case StrategySnapRound:
+		return "snap-round"
+	}
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// algorithmError is a failure of the sweep itself rather than of the input,
+// the kind a more robust strategy may get past.
+type algorithmError struct {
+	msg string
+}
+
+func newAlgorithmError(format string, a ...interface{}) error {
+	return &algorithmError{msg: fmt.Sprintf(format, a...)}
+}
+
+func (e *algorithmError) Error() string {
+	return e.msg
+}
+
+// sweepFallback retries a sweep that failed with err using each of the
+// fallback strategies in turn. The original error is returned if none of
+// them succeed.
+func (o *operation) sweepFallback(err error, geom Geom, moreGeoms []Geom) ([]*ringOut, []*polyOut, error) {
+	var algErr *algorithmError
+	if !errors.As(err, &algErr) {
+		return nil, nil, err
+	}
+
+	strategies := []Strategy{}
+	if !o.opts.robust {
+		strategies = append(strategies, StrategyRobust)
+	}
+	strategies = append(strategies, StrategySnapRound)
+
+	for i := 0; i < len(strategies); i++ {
+		// nothing the failed sweeps allocated is used anymore
+		o.forgetMemory(0)
+		retry := o.fallbackOperation(strategies[i], geom, moreGeoms)
+		ringsOut, passedPolys, retryErr := retry.sweepOnce(geom, moreGeoms...)
+		if retryErr == nil {
+			o.strategy = strategies[i]
+			return ringsOut, passedPolys, nil
+		}
+		if !errors.As(retryErr, &algErr) {
+			return nil, nil, retryErr
+		}
+	}
+	return nil, nil, err
+}
+
+// fallbackOperation sets up a fresh operation like o, but carried out with
+// the given strategy.
+func (o *operation) fallbackOperation(strategy Strategy, geom Geom, moreGeoms []Geom) *operation {
+	retry := newOperation(o.opType)
+	retry.nesting = o.nesting
+	retry.opts = o.opts
+	retry.origin = o.origin
+	retry.scope = o.scope
+	switch strategy {
+	case StrategyRobust:
+		retry.opts.robust = true
+	case StrategySnapRound:
+		retry.opts.robust = true
+		retry.opts.precision = fallbackGridSize(o.opts.precision, geom, moreGeoms)
+	}
+	return retry
+}
+
+// fallbackGridSize picks a snap rounding grid coarser than precision, or if
+// that's unset, a power of ten about nine digits below the largest input
+// coordinate.
+func fallbackGridSize(precision float64, geom Geom, moreGeoms []Geom) float64 {
+	if precision > 0 {
+		return precision * 10
+	}
+	maxAbs := 0.0
+	geoms := append([]Geom{geom}, moreGeoms...)
+	for i := 0; i < len(geoms); i++ {
+		for j := 0; j < len(geoms[i]); j++ {
+			for k := 0; k < len(geoms[i][j]); k++ {
+				for l := 0; l < len(geoms[i][j][k]); l++ {
+					position := geoms[i][j][k][l]
+					for m := 0; m < len(position) && m < 2; m++ {
+						maxAbs = math.Max(maxAbs, math.Abs(position[m]))
+					}
+				}
+			}
+		}
+	}
+	if maxAbs == 0 || math.IsInf(maxAbs, 0) || math.IsNaN(maxAbs) {
+		return 1e-9
+	}
+	return math.Pow(10, math.Floor(math.Log10(maxAbs))-9)
+}
@@ -0,0 +1,130 @@
+package polygol
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFallbackGridSize(t *testing.T) {
+	t.Parallel()
+
+	geom := Geom{{{{-120, 30}, {-119, 30}, {-119, 31}, {-120, 30}}}}
+	expect(t, fallbackGridSize(0, geom, nil) == 1e-7)
+	expect(t, fallbackGridSize(0.5, geom, nil) == 5)
+
+	utm := Geom{{{{500000, 4649776}, {500010, 4649776}, {500010, 4649786}, {500000, 4649776}}}}
+	expect(t, fallbackGridSize(0, geom, []Geom{utm}) == 1e-3)
+
+	expect(t, fallbackGridSize(0, Geom{}, nil) == 1e-9)
+}
+
+func TestSweepFallback(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}
+	b := Geom{{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}}
+
+	t.Run("retries algorithm errors", func(t *testing.T) {
+		t.Parallel()
+		o := New(WithFallback()).newOperation("union")
+		ringsOut, _, err := o.sweepFallback(newAlgorithmError("Unable to pop()"), a, []Geom{b})
+		terr(t, err)
+		expect(t, len(ringsOut) == 1)
+		expect(t, o.strategy == StrategyRobust)
+		expect(t, ringsOut[0].op.opts.robust)
+	})
+
+	t.Run("skips strategies already in use", func(t *testing.T) {
+		t.Parallel()
+		o := New(WithFallback(), WithRobustPredicates()).newOperation("union")
+		ringsOut, _, err := o.sweepFallback(newAlgorithmError("Unable to pop()"), a, []Geom{b})
+		terr(t, err)
+		expect(t, len(ringsOut) == 1)
+		expect(t, o.strategy == StrategySnapRound)
+		expect(t, ringsOut[0].op.opts.precision == 1e-9)
+	})
+
+	t.Run("passes other errors through", func(t *testing.T) {
+		t.Parallel()
+		inputErr := errors.New("bad input")
+		o := New(WithFallback()).newOperation("union")
+		_, _, err := o.sweepFallback(inputErr, a, []Geom{b})
+		expect(t, err == inputErr)
+		expect(t, o.strategy == StrategyDefault)
+	})
+
+	t.Run("passes polygons through once", func(t *testing.T) {
+		t.Parallel()
+		c := square(10, 10, 1)
+		o := New(WithFallback(), WithPassThrough()).newOperation("union")
+		_, passedPolys, err := o.sweepOnce(a, b, c)
+		terr(t, err)
+		expect(t, len(passedPolys) == 1)
+		ringsOut, passedPolys, err := o.sweepFallback(newAlgorithmError("Unable to pop()"), a, []Geom{b, c})
+		terr(t, err)
+		expect(t, len(ringsOut) == 1)
+		expect(t, len(passedPolys) == 1)
+		expect(t, len(o.getGeom(ringsOut, passedPolys)) == 2)
+	})
+
+	t.Run("reports the default strategy", func(t *testing.T) {
+		t.Parallel()
+		result, err := New(WithFallback()).Run(OpUnion, a, b)
+		terr(t, err)
+		expect(t, result.Strategy == StrategyDefault)
+		expect(t, result.Strategy.String() == "default")
+	})
+}
+
+func TestFallbackRecovers(t *testing.T) {
+	t.Parallel()
+
+	// slivers along nearly the same line, whose edges float predicates can't
+	// order consistently
+	testCases := []struct {
+		name     string
+		geoms    []Geom
+		strategy Strategy
+		retry    *Polygol // set up like the retry that succeeds
+	}{
+		{
+			name: "robust",
+			geoms: []Geom{
+				{{{{2, 1.4834877669322308}, {5.6, 4.153765747562685}, {0.5, 0.3708719417330577}, {2, 1.4834877669322308}}}},
+				{{{{8.1, 6.008125456075534}, {5.8, 4.3021145241034695}, {2.7, 2.0027084853585118}, {8.1, 6.008125456075534}}}},
+				{{{{6, 4.4504633007966925}, {2, 1.4834877669322308}, {6.6, -1.4}, {6, 4.4504633007966925}}}},
+			},
+			strategy: StrategyRobust,
+			retry:    New(WithRobustPredicates()),
+		},
+		{
+			name: "snap-round",
+			geoms: []Geom{
+				{{{{6, 3.4130053942284286}, {9.9, 5.631458900765149}, {2.2, 1.2514353114375198}, {6, 3.4130053942284286}}}},
+				{{{{7.3, 4.152489896820185}, {7.3, 4.152489897068134}, {7.6, -2.4}, {7.3, 4.152489896820185}}}},
+				{{{{5.4, 3.0717048551820554}, {7.8, 4.436907013107189}, {5.6, 3.1854717011888525}, {5.4, 3.0717048551820554}}}},
+			},
+			strategy: StrategySnapRound,
+			retry:    New(WithRobustPredicates(), WithPrecision(1e-9)),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Union(tc.geoms[0], tc.geoms[1:]...)
+			var algErr *algorithmError
+			expect(t, errors.As(err, &algErr))
+
+			result, err := New(WithFallback()).Run(OpUnion, tc.geoms[0], tc.geoms[1:]...)
+			terr(t, err)
+			expect(t, result.Strategy == tc.strategy)
+			expect(t, len(result.Geom) > 0)
+			want, err := tc.retry.Union(tc.geoms[0], tc.geoms[1:]...)
+			terr(t, err)
+			expect(t, reflect.DeepEqual(result.Geom, want))
+		})
+	}
+}
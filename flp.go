@@ -35,3 +35,86 @@ func flpCmp(a, b float64) int {
 func almostEqual(a, b float64) bool {
 	return math.Abs(a-b) <= epsilon
 }
+
+type toleranceKind int
+
+const (
+	toleranceDefault toleranceKind = iota
+	toleranceRelative
+	toleranceAbsolute
+	toleranceULP
+)
+
+// Tolerance decides when two coordinates are close enough to be treated as
+// equal. The zero value is the default: absolute near zero and relative
+// elsewhere, with an epsilon of 2e-12.
+type Tolerance struct {
+	kind  toleranceKind
+	value float64
+}
+
+// RelativeTolerance treats coordinates as equal if they differ by at most
+// eps times the larger of their magnitudes. Suits geographic coordinates.
+func RelativeTolerance(eps float64) Tolerance {
+	return Tolerance{kind: toleranceRelative, value: eps}
+}
+
+// AbsoluteTolerance treats coordinates as equal if they differ by at most
+// eps. Suits projected data in known units, such as millimeter CAD data.
+func AbsoluteTolerance(eps float64) Tolerance {
+	return Tolerance{kind: toleranceAbsolute, value: eps}
+}
+
+// ULPTolerance treats coordinates as equal if there are at most ulps
+// floating point numbers between them.
+func ULPTolerance(ulps uint64) Tolerance {
+	return Tolerance{kind: toleranceULP, value: float64(ulps)}
+}
+
+// cmp is flpCmp under the tolerance.
+func (t Tolerance) cmp(a, b float64) int {
+	if t.kind == toleranceDefault {
+		return flpCmp(a, b)
+	}
+	if t.equal(a, b) {
+		return 0
+	}
+	if a < b {
+		return -1
+	}
+	return 1
+}
+
+// equal is almostEqual under the tolerance.
+func (t Tolerance) equal(a, b float64) bool {
+	switch t.kind {
+	case toleranceRelative:
+		return math.Abs(a-b) <= t.value*math.Max(math.Abs(a), math.Abs(b))
+	case toleranceAbsolute:
+		return math.Abs(a-b) <= t.value
+	case toleranceULP:
+		return a == b || float64(ulpDistance(a, b)) <= t.value
+	}
+	return almostEqual(a, b)
+}
+
+// ulpDistance counts the floating point numbers from a to b.
+func ulpDistance(a, b float64) uint64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.MaxUint64
+	}
+	// map the bits onto a scale where adjacent floats are adjacent
+	// integers, with negative numbers below positive ones
+	ordered := func(f float64) int64 {
+		bits := int64(math.Float64bits(f))
+		if bits < 0 {
+			return math.MinInt64 - bits
+		}
+		return bits
+	}
+	ia, ib := ordered(a), ordered(b)
+	if ia > ib {
+		ia, ib = ib, ia
+	}
+	return uint64(ib) - uint64(ia)
+}
@@ -1,6 +1,9 @@
 package polygol
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestFlpCompare(t *testing.T) {
 	var a, b float64
@@ -45,3 +48,82 @@ func TestFlpCompare(t *testing.T) {
 	b = epsilon + epsilon*epsilon*2
 	expect(t, flpCmp(a, b) == -1)
 }
+
+func TestTolerance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		tol := Tolerance{}
+		expect(t, tol.cmp(1, 1+epsilon) == 0)
+		expect(t, tol.cmp(1, 1+epsilon*2) == -1)
+		expect(t, tol.equal(0, epsilon))
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		t.Parallel()
+		tol := RelativeTolerance(1e-6)
+		expect(t, tol.cmp(1e6, 1e6+0.5) == 0)
+		expect(t, tol.cmp(1e6, 1e6+2) == -1)
+		expect(t, tol.cmp(1e-6, 2e-6) == -1)
+		expect(t, tol.cmp(0, 1e-300) == -1)
+	})
+
+	t.Run("absolute", func(t *testing.T) {
+		t.Parallel()
+		tol := AbsoluteTolerance(1e-3)
+		expect(t, tol.cmp(1e6, 1e6+1e-4) == 0)
+		expect(t, tol.cmp(0, 1e-4) == 0)
+		expect(t, tol.cmp(2, 1) == 1)
+		expect(t, !tol.equal(1, 1.01))
+	})
+
+	t.Run("ulp", func(t *testing.T) {
+		t.Parallel()
+		tol := ULPTolerance(2)
+		expect(t, tol.cmp(1, math.Nextafter(math.Nextafter(1, 2), 2)) == 0)
+		expect(t, tol.cmp(1, 1+1e-15) == -1)
+		expect(t, tol.cmp(0, math.Copysign(0, -1)) == 0)
+		expect(t, tol.cmp(math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64) == 0)
+		expect(t, tol.cmp(math.NaN(), 1) != 0)
+	})
+}
+
+func TestUlpDistance(t *testing.T) {
+	t.Parallel()
+
+	expect(t, ulpDistance(1, 1) == 0)
+	expect(t, ulpDistance(1, math.Nextafter(1, 2)) == 1)
+	expect(t, ulpDistance(math.Nextafter(1, 2), 1) == 1)
+	expect(t, ulpDistance(-math.SmallestNonzeroFloat64, math.SmallestNonzeroFloat64) == 2)
+}
+
+func TestWithTolerance(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}
+	b := Geom{{{{1.0001, 0}, {2, 0}, {2, 1}, {1.0001, 1}, {1.0001, 0}}}}
+
+	union, err := Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 2)
+
+	union, err = New(WithTolerance(AbsoluteTolerance(1e-3))).Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 1)
+}
+
+func TestWithToleranceColinear(t *testing.T) {
+	t.Parallel()
+
+	// the bottom edge bends by less than the tolerance at (5, 0.0001)
+	a := Geom{{{{0, 0}, {5, 0.0001}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}}
+
+	union, err := Union(a)
+	terr(t, err)
+	expect(t, len(union[0][0]) == 6)
+
+	union, err = New(WithTolerance(AbsoluteTolerance(1e-3))).Union(a)
+	terr(t, err)
+	expect(t, len(union[0][0]) == 5)
+}
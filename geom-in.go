@@ -7,6 +7,7 @@ import (
 
 type ringIn struct {
 	poly       *polyIn
+	index      int
 	isExterior bool
 	segments   []*segment
 	bbox       bbox
@@ -25,29 +26,23 @@ func (o *operation) newRingIn(ring [][]float64, poly *polyIn, isExterior bool) (
 
 	ri.poly = poly
 	ri.isExterior = isExterior
-	ri.segments = []*segment{}
+	ri.segments = make([]*segment, 0, len(ring))
 
-	firstPoint := o.rounder.round(ring[0][0], ring[0][1])
+	firstPoint := o.newInputPoint(ring[0])
+	firstPoint.sources = []vertexSource{{ring: ri, index: 0}}
 
 	ri.bbox = bbox{ll: *firstPoint, ur: *firstPoint}
 
 	prevPoint := firstPoint
-	for i := 1; i < len(ring); i++ {
-
-		if len(ring[i]) < 2 {
-			return nil, fmt.Errorf(`Input geometry is not a valid polygon or multipolygon (missing coordinates).`)
-		}
-
-		point := o.rounder.round(ring[i][0], ring[i][1])
-
+	addPoint := func(point *point) error {
 		// skip repeated points
 		if point.x == prevPoint.x && point.y == prevPoint.y {
-			continue
+			return nil
 		}
 
 		segment, err := o.newSegmentFromRing(prevPoint, point, ri)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		ri.segments = append(ri.segments, segment)
 
@@ -64,9 +59,47 @@ func (o *operation) newRingIn(ring [][]float64, poly *polyIn, isExterior bool) (
 			ri.bbox.ur.y = point.y
 		}
 		prevPoint = point
+		return nil
+	}
+	// when snap rounding, edges are routed through the hot pixels they cross
+	addHotPixels := func(from, to []float64) error {
+		if o.hotPixels == nil {
+			return nil
+		}
+		positions := o.hotPixels.route(from, to)
+		for j := 0; j < len(positions); j++ {
+			if err := addPoint(o.newInputPoint(positions[j])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := 1; i < len(ring); i++ {
+
+		if len(ring[i]) < 2 {
+			return nil, fmt.Errorf(`Input geometry is not a valid polygon or multipolygon (missing coordinates).`)
+		}
+
+		point := o.newInputPoint(ring[i])
+
+		// the closing point is the same vertex as the first
+		if i != len(ring)-1 || point.x != firstPoint.x || point.y != firstPoint.y {
+			point.sources = []vertexSource{{ring: ri, index: i}}
+		}
+
+		if err := addHotPixels(ring[i-1], ring[i]); err != nil {
+			return nil, err
+		}
+		if err := addPoint(point); err != nil {
+			return nil, err
+		}
 	}
 	// add segment from last to first if last is not the same as first
 	if firstPoint.x != prevPoint.x || firstPoint.y != prevPoint.y {
+		if err := addHotPixels(ring[len(ring)-1], ring[0]); err != nil {
+			return nil, err
+		}
 		segment, err := o.newSegmentFromRing(prevPoint, firstPoint, ri)
 		if err != nil {
 			return nil, err
@@ -76,6 +109,37 @@ func (o *operation) newRingIn(ring [][]float64, poly *polyIn, isExterior bool) (
 	return ri, nil
 }
 
+// checkFinite fails on NaN or infinite coordinates anywhere in the geom,
+// which the sweep has no way to place.
+func checkFinite(geom Geom) error {
+	for i := 0; i < len(geom); i++ {
+		for j := 0; j < len(geom[i]); j++ {
+			ring := geom[i][j]
+			for k := 0; k < len(ring); k++ {
+				for m := 0; m < len(ring[k]) && m < 2; m++ {
+					if math.IsInf(ring[k][m], 0) || math.IsNaN(ring[k][m]) {
+						return fmt.Errorf(`Input geometry is not a valid polygon or multipolygon (non-finite coordinates).`)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newInputPoint rounds an input position to a point, keeping any Z/M values.
+func (o *operation) newInputPoint(position []float64) *point {
+	x, y := position[0], position[1]
+	if o.hotPixels != nil {
+		x, y = o.hotPixels.snap(x), o.hotPixels.snap(y)
+	}
+	point := o.round(x, y)
+	if len(position) > 2 {
+		point.zm = append([]float64{}, position[2:]...)
+	}
+	return point
+}
+
 func (ri *ringIn) getSweepEvents() []*sweepEvent {
 	sweepEvents := []*sweepEvent{}
 	for i := 0; i < len(ri.segments); i++ {
@@ -99,6 +163,7 @@ func (ri *ringIn) indexOf(ringIns []*ringIn) int {
 
 type polyIn struct {
 	multiPoly     *multiPolyIn
+	index         int
 	exteriorRing  *ringIn
 	interiorRings []*ringIn
 	bbox          bbox
@@ -126,6 +191,7 @@ func (o *operation) newPolyIn(poly [][][]float64, multiPoly *multiPolyIn) (*poly
 		if err != nil {
 			return nil, err
 		}
+		ring.index = i
 		if ring.bbox.ll.x < pi.bbox.ll.x {
 			pi.bbox.ll.x = ring.bbox.ll.x
 		}
@@ -166,6 +232,7 @@ func (pi *polyIn) indexOf(polyIns []*polyIn) int {
 }
 
 type multiPolyIn struct {
+	index     int
 	polys     []*polyIn
 	bbox      bbox
 	isSubject bool
@@ -186,6 +253,7 @@ func (o *operation) newMultiPolyIn(multiPoly [][][][]float64, isSubject bool) (*
 		if err != nil {
 			return nil, err
 		}
+		poly.index = i
 		if poly.bbox.ll.x < mpi.bbox.ll.x {
 			mpi.bbox.ll.x = poly.bbox.ll.x
 		}
@@ -170,3 +170,38 @@ func TestGeomInMultiPolyInIndexOf(t *testing.T) {
 	expect(t, mp5.indexOf(multiPolyIns) == 4)
 	expect(t, mp6.indexOf(multiPolyIns) == -1)
 }
+
+func TestGeomInZM(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0, 0}, {2, 0, 0}, {2, 2, 10}, {0, 2, 10}, {0, 0, 0}}}}
+	b := Geom{{{{1, 1, 5}, {3, 1, 5}, {3, 3, 5}, {1, 3, 5}, {1, 1, 5}}}}
+
+	result, err := Union(a, b)
+	terr(t, err)
+	expect(t, len(result) == 1)
+
+	ring := result[0][0]
+	found := false
+	for _, pos := range ring {
+		expect(t, len(pos) == 3)
+		switch {
+		case pos[0] == 0 && pos[1] == 0:
+			expect(t, pos[2] == 0)
+		case pos[0] == 3 && pos[1] == 3:
+			expect(t, pos[2] == 5)
+		case pos[0] == 2 && pos[1] == 1:
+			// intersection point, interpolated along either edge
+			found = true
+			expect(t, pos[2] == 5)
+		}
+	}
+	expect(t, found)
+
+	// M values come along too
+	c := Geom{{{{0, 0, 1, 100}, {4, 0, 1, 200}, {4, 4, 1, 300}, {0, 4, 1, 400}, {0, 0, 1, 100}}}}
+	d := Geom{{{{2, -1}, {5, -1}, {5, 5}, {2, 5}, {2, -1}}}}
+	result, err = Difference(c, d)
+	terr(t, err)
+	expect(t, equalMultiPoly(result, Geom{{{{0, 0, 1, 100}, {2, 0, 1, 150}, {2, 4, 1, 350}, {0, 4, 1, 400}, {0, 0, 1, 100}}}}))
+}
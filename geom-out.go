@@ -1,8 +1,7 @@
 package polygol
 
 import (
-	"fmt"
-	"sort"
+	"math"
 )
 
 type linkedEvent struct {
@@ -15,6 +14,7 @@ type geomOut interface {
 }
 
 type ringOut struct {
+	op                *operation
 	geom              [][]float64
 	forceGeom         bool
 	events            []*sweepEvent
@@ -25,7 +25,13 @@ type ringOut struct {
 }
 
 func newRingOut(events []*sweepEvent) *ringOut {
-	ro := &ringOut{}
+	var ro *ringOut
+	if len(events) > 0 && events[0].segment.op != nil {
+		ro = events[0].segment.op.alloc().ringsOut.alloc()
+		ro.op = events[0].segment.op
+	} else {
+		ro = &ringOut{}
+	}
 	ro.events = events
 	for i := 0; i < len(events); i++ {
 		events[i].segment.ringOut = ro
@@ -37,6 +43,10 @@ func newRingOutFromSegments(allSegments []*segment) ([]*ringOut, error) {
 
 	ringsOut := []*ringOut{}
 
+	// buffers reused at every junction
+	var availableLEs []*sweepEvent
+	var anglesBuf []angles
+
 	for i := 0; i < len(allSegments); i++ {
 
 		segment := allSegments[i]
@@ -66,14 +76,14 @@ func newRingOutFromSegments(allSegments []*segment) ([]*ringOut, error) {
 			}
 
 			for {
-				availableLEs := event.getAvailableLinkedEvents()
+				availableLEs = event.getAvailableLinkedEvents(availableLEs[:0])
 
 				// Did we hit a dead end? This shouldn't happen. Indicates some earlier
 				//  part of the algorithm malfunctioned... please file a bug report.
 				if len(availableLEs) == 0 {
 					firstPt := events[0].point
 					lastPt := events[len(events)-1].point
-					return nil, fmt.Errorf(`Unable to complete output ring starting at [%f, %f].
+					return nil, newAlgorithmError(`Unable to complete output ring starting at [%f, %f].
 					Last matching segment found ends at [%f, %f].`,
 						firstPt.x, firstPt.y, lastPt.x, lastPt.y)
 				}
@@ -113,10 +123,7 @@ func newRingOutFromSegments(allSegments []*segment) ([]*ringOut, error) {
 				intersectionLEs = append(intersectionLEs, linkedEvent{index: len(events), point: event.point})
 
 				// Choose the left-most option to continue the walk.
-				comparator := event.getLeftMostComparator(prevEvent)
-				sort.SliceStable(availableLEs, func(i, j int) bool {
-					return comparator(availableLEs[i], availableLEs[j]) < 0
-				})
+				anglesBuf = event.sortLeftMost(prevEvent, availableLEs, anglesBuf)
 				nextEvent = availableLEs[0].otherSE
 				break
 			}
@@ -130,59 +137,159 @@ func (ro *ringOut) getGeom() [][]float64 {
 	if ro.forceGeom {
 		return ro.geom
 	}
-	// Remove superfluous points (ie extra points along a straight line),
-	prevPt := ro.events[0].point
-	points := []*point{prevPt}
-	for i := 1; i < len(ro.events)-1; i++ {
-		pt := ro.events[i].point
-		nextPt := ro.events[i+1].point
-		if compareAngles(
-			[]float64{pt.x, pt.y},
-			[]float64{prevPt.x, prevPt.y},
-			[]float64{nextPt.x, nextPt.y},
-		) == 0 {
-			continue
-		}
-		points = append(points, pt)
-		prevPt = pt
-	}
-
+	indexes := ro.getPointIndexes()
 	// ring was all (within rounding error of angle calc) colinear points
-	if len(points) == 1 {
+	if indexes == nil {
 		return nil
 	}
-
-	// check if the starting point is necessary
-	pt := points[0]
-	nextPt := points[1]
-	if compareAngles(
-		[]float64{pt.x, pt.y},
-		[]float64{prevPt.x, prevPt.y},
-		[]float64{nextPt.x, nextPt.y},
-	) == 0 {
-		points = points[1:]
+	// ring was too small or thin to keep
+	if ro.isSliver(indexes) {
+		return nil
+	}
+	points := make([]*point, 0, len(indexes)+1)
+	for i := 0; i < len(indexes); i++ {
+		points = append(points, ro.events[indexes[i]].point)
 	}
 
 	points = append(points, points[0])
+	isForward := ro.calcIsExteriorRing()
+	if ro.op != nil && ro.op.opts.winding == WindingESRI {
+		isForward = !isForward
+	}
 	step := -1
-	if ro.calcIsExteriorRing() {
+	if isForward {
 		step = 1
 	}
 	iStart := len(points) - 1
-	if ro.calcIsExteriorRing() {
+	if isForward {
 		iStart = 0
 	}
 	iEnd := -1
-	if ro.calcIsExteriorRing() {
+	if isForward {
 		iEnd = len(points)
 	}
+	if ro.op != nil && ro.op.opts.openRings {
+		iEnd -= step
+	}
 	orderedPoints := [][]float64{}
 	for i := iStart; i != iEnd; i += step {
-		orderedPoints = append(orderedPoints, []float64{points[i].x, points[i].y})
+		position := points[i].position()
+		position[0], position[1] = ro.outputXY(points[i])
+		orderedPoints = append(orderedPoints, position)
 	}
 	return orderedPoints
 }
 
+// getPointIndexes finds the events whose points make it into the output
+// ring, in ring order. Returns nil if the ring was all (within rounding
+// error of angle calc) colinear points.
+func (ro *ringOut) getPointIndexes() []int {
+	// Remove superfluous points (ie extra points along a straight line),
+	// unless they're vertices we've been asked to keep
+	prevPt := ro.events[0].point
+	indexes := []int{0}
+	numCorners := 0
+	for i := 1; i < len(ro.events)-1; i++ {
+		pt := ro.events[i].point
+		nextPt := ro.events[i+1].point
+		if ro.compareAngles(pt.xy(), prevPt.xy(), nextPt.xy()) == 0 {
+			if !ro.keepVertex(pt) {
+				continue
+			}
+		} else {
+			numCorners++
+		}
+		indexes = append(indexes, i)
+		prevPt = pt
+	}
+
+	if numCorners == 0 {
+		return nil
+	}
+
+	// check if the starting point is necessary
+	pt := ro.events[indexes[0]].point
+	nextPt := ro.events[indexes[1]].point
+	if ro.compareAngles(pt.xy(), prevPt.xy(), nextPt.xy()) == 0 && !ro.keepVertex(pt) {
+		indexes = indexes[1:]
+	}
+	return indexes
+}
+
+// compareAngles picks the colinearity test configured for the operation.
+func (ro *ringOut) compareAngles(basePt, endPt1, endPt2 vector) int {
+	if ro.op != nil && ro.op.opts.robust {
+		return compareAnglesRobust(basePt, endPt1, endPt2)
+	}
+	tol := Tolerance{}
+	if ro.op != nil {
+		tol = ro.op.opts.tolerance
+	}
+	return compareAngles(basePt, endPt1, endPt2, tol)
+}
+
+// keepVertex reports whether a point along a straight run of the ring
+// should be kept in the output anyway.
+func (ro *ringOut) keepVertex(pt *point) bool {
+	if ro.op == nil {
+		return false
+	}
+	switch ro.op.opts.vertices {
+	case VerticesInput:
+		return pt.isInput()
+	case VerticesAll:
+		return true
+	}
+	return false
+}
+
+// isSliver reports whether the ring made of the points at indexes falls
+// below the minimum area or thinness asked for.
+func (ro *ringOut) isSliver(indexes []int) bool {
+	if ro.op == nil || (ro.op.opts.minArea <= 0 && ro.op.opts.minThinness <= 0) {
+		return false
+	}
+	area, perimeter := 0.0, 0.0
+	for i := 0; i < len(indexes); i++ {
+		a := ro.events[indexes[i]].point
+		b := ro.events[indexes[(i+1)%len(indexes)]].point
+		area += a.x*b.y - b.x*a.y
+		perimeter += math.Hypot(b.x-a.x, b.y-a.y)
+	}
+	area = math.Abs(area) / 2
+	if area < ro.op.opts.minArea {
+		return true
+	}
+	return perimeter > 0 && area/(perimeter*perimeter) < ro.op.opts.minThinness
+}
+
+// isInSliver reports whether the ring lies within a hole dropped for being
+// a sliver. The polygon around that hole covers the ring then, so the ring
+// is dropped too rather than left overlapping it.
+func (ro *ringOut) isInSliver() bool {
+	if ro.op == nil || (ro.op.opts.minArea <= 0 && ro.op.opts.minThinness <= 0) {
+		return false
+	}
+	for enclosing := ro.parentRing(); enclosing != nil; enclosing = enclosing.parentRing() {
+		if enclosing.calcIsExteriorRing() {
+			continue
+		}
+		if indexes := enclosing.getPointIndexes(); indexes != nil && enclosing.isSliver(indexes) {
+			return true
+		}
+	}
+	return false
+}
+
+// parentRing is the enclosing ring, without looking for one around rings
+// that didn't come from the sweep.
+func (ro *ringOut) parentRing() *ringOut {
+	if ro.forceExteriorRing {
+		return ro.enclosingRing
+	}
+	return ro.getEnclosingRing()
+}
+
 func (ro *ringOut) calcIsExteriorRing() bool {
 	if ro.forceExteriorRing {
 		return ro.isExteriorRing
@@ -281,6 +388,9 @@ func (po *polyOut) getGeom() [][][]float64 {
 	if po.forceGeom {
 		return po.geom
 	}
+	if po.exteriorRing.isInSliver() {
+		return nil
+	}
 	geom := [][][]float64{po.exteriorRing.getGeom()}
 	// exterior ring was all (within rounding error of angle calc) colinear points
 	if geom == nil {
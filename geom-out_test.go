@@ -733,3 +733,179 @@ func TestGeomOutMultiPolyColinearExteriorRing(t *testing.T) {
 
 	expect(t, equalMultiPoly(multiPoly.getGeom(), [][][][]float64{{{{1}}}}))
 }
+
+func TestGeomOutRingWindingAndClosure(t *testing.T) {
+	t.Parallel()
+
+	outer := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	hole := [][]float64{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}
+	geom := Geom{{outer, hole}}
+
+	t.Run("rfc7946", func(t *testing.T) {
+		result, err := New(WithWinding(WindingRFC7946)).Union(geom)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{outer, hole}}))
+	})
+
+	t.Run("esri", func(t *testing.T) {
+		result, err := New(WithWinding(WindingESRI)).Union(geom)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{
+			{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}},
+			{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}},
+		}}))
+	})
+
+	t.Run("open", func(t *testing.T) {
+		result, err := New(WithOpenRings()).Union(geom)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{outer[:4], hole[:4]}}))
+	})
+
+	t.Run("esri-open", func(t *testing.T) {
+		result, err := New(WithWinding(WindingESRI), WithOpenRings()).Union(geom)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{
+			{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+			{{2, 2}, {8, 2}, {8, 8}, {2, 8}},
+		}}))
+	})
+}
+
+func TestGeomOutRingKeepVertices(t *testing.T) {
+	t.Parallel()
+
+	// square with an input vertex halfway along its bottom edge
+	// and an intersection point halfway along its top edge
+	newRing := func(vertices Vertices) *ringOut {
+		op := newOperation("")
+		op.opts.vertices = vertices
+
+		pts := []*point{
+			newPoint(0, 0),
+			newPoint(1, 0),
+			newPoint(2, 0),
+			newPoint(2, 2),
+			newPoint(1, 2),
+			newPoint(0, 2),
+		}
+		for i := 0; i < len(pts); i++ {
+			if i != 4 {
+				pts[i].sources = []vertexSource{{index: i}}
+			}
+		}
+		segs := []*segment{}
+		for i := 0; i < len(pts); i++ {
+			seg, err := op.newSegmentFromRing(pts[i], pts[(i+1)%len(pts)], &ringIn{})
+			terr(t, err)
+			seg.forceInResult, seg.inResult = true, true
+			segs = append(segs, seg)
+		}
+		rings, err := newRingOutFromSegments(segs)
+		terr(t, err)
+		expect(t, len(rings) == 1)
+		return rings[0]
+	}
+
+	t.Run("simplified", func(t *testing.T) {
+		expect(t, equalRing(newRing(VerticesSimplified).getGeom(),
+			[][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}))
+	})
+
+	t.Run("input", func(t *testing.T) {
+		expect(t, equalRing(newRing(VerticesInput).getGeom(),
+			[][]float64{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}))
+	})
+
+	t.Run("all", func(t *testing.T) {
+		expect(t, equalRing(newRing(VerticesAll).getGeom(),
+			[][]float64{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {1, 2}, {0, 2}, {0, 0}}))
+	})
+
+	t.Run("input-from-other-geom", func(t *testing.T) {
+		a := Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}
+		b := Geom{{{{1, 2}, {3, 2}, {3, 4}, {1, 4}, {1, 2}}}}
+		result, err := New(WithVertices(VerticesInput)).Difference(a, b)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{{{0, 0}, {2, 0}, {2, 2}, {1, 2}, {0, 2}, {0, 0}}}}))
+	})
+
+	t.Run("all-colinear", func(t *testing.T) {
+		p1 := newPoint(0, 0)
+		p2 := newPoint(1, 1)
+		p3 := newPoint(2, 2)
+		op := newOperation("")
+		op.opts.vertices = VerticesAll
+		seg1, err := op.newSegmentFromRing(p1, p2, &ringIn{})
+		terr(t, err)
+		seg2, err := op.newSegmentFromRing(p2, p3, &ringIn{})
+		terr(t, err)
+		seg3, err := op.newSegmentFromRing(p3, p1, &ringIn{})
+		terr(t, err)
+		seg1.forceInResult, seg1.inResult = true, true
+		seg2.forceInResult, seg2.inResult = true, true
+		seg3.forceInResult, seg3.inResult = true, true
+		rings, err := newRingOutFromSegments([]*segment{seg1, seg2, seg3})
+		terr(t, err)
+		expect(t, rings[0].getGeom() == nil)
+	})
+}
+
+func TestGeomOutSliverRemoval(t *testing.T) {
+	t.Parallel()
+
+	// a square with a tiny hole, next to a long thin strip
+	square := Geom{{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{5, 5}, {5, 5.1}, {5.1, 5.1}, {5.1, 5}, {5, 5}},
+	}}
+	strip := Geom{{{{20, 0}, {30, 0}, {30, 0.1}, {20, 0.1}, {20, 0}}}}
+
+	union, err := Union(square, strip)
+	terr(t, err)
+	expect(t, len(union) == 2)
+	expect(t, len(union[0]) == 2)
+
+	union, err = New(WithMinArea(0.5)).Union(square, strip)
+	terr(t, err)
+	expect(t, len(union) == 2)
+	expect(t, len(union[0]) == 1)
+
+	union, err = New(WithMinThinness(0.01)).Union(square, strip)
+	terr(t, err)
+	expect(t, len(union) == 1)
+	expect(t, len(union[0]) == 2)
+
+	union, err = New(WithMinArea(0.5), WithMinThinness(0.01)).Union(square, strip)
+	terr(t, err)
+	expect(t, len(union) == 1)
+	expect(t, len(union[0]) == 1)
+	expect(t, union[0][0][0][0] == 0)
+}
+
+func TestGeomOutSliverHoleIsland(t *testing.T) {
+	t.Parallel()
+
+	// a thin hole with an island in it, which is left covered by the square
+	// once the hole is dropped
+	square := Geom{{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{2, 4.9}, {8, 4.9}, {8, 5.3}, {2, 5.3}, {2, 4.9}},
+	}}
+	island := Geom{{{{4, 5}, {5, 5}, {5, 5.2}, {4, 5.2}, {4, 5}}}}
+
+	union, err := Union(square, island)
+	terr(t, err)
+	expect(t, len(union) == 2)
+
+	p := New(WithMinThinness(0.02))
+	union, err = p.Union(square, island)
+	terr(t, err)
+	expect(t, len(union) == 1)
+	expect(t, len(union[0]) == 1)
+
+	tree, err := p.Tree(OpUnion, square, island)
+	terr(t, err)
+	expect(t, len(tree.Children) == 1)
+	expect(t, len(tree.Children[0].Children) == 0)
+}
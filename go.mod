@@ -1,5 +1,3 @@
 module github.com/engelsjk/polygol
 
-go 1.17
-
-require github.com/engelsjk/splay-tree v0.0.1
+go 1.18
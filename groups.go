@@ -0,0 +1,292 @@
+package polygol
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// forEachOverlap calls fn for every pair of bboxes that overlap once grown
+// by margin, found by sweeping them from left to right, with i < j.
+func forEachOverlap(bboxes []bbox, margin float64, fn func(i, j int)) {
+	order := make([]int, len(bboxes))
+	for i := 0; i < len(order); i++ {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return bboxes[order[a]].ll.x < bboxes[order[b]].ll.x
+	})
+	// only the bboxes not yet passed by the sweep need checking
+	open := []int{}
+	for _, i := range order {
+		b := bboxes[i]
+		if b.ll.x > b.ur.x {
+			// empty
+			continue
+		}
+		stillOpen := open[:0]
+		for _, j := range open {
+			ob := bboxes[j]
+			if ob.ur.x+margin < b.ll.x-margin {
+				continue
+			}
+			stillOpen = append(stillOpen, j)
+			if ob.ur.y+margin >= b.ll.y-margin && b.ur.y+margin >= ob.ll.y-margin {
+				if i < j {
+					fn(i, j)
+				} else {
+					fn(j, i)
+				}
+			}
+		}
+		open = append(stillOpen, i)
+	}
+}
+
+// prunePolys drops polygons that can't contribute to the result: for an
+// intersection, those that don't overlap some polygon of every other input,
+// and for a difference, clipping polygons that don't overlap the subject.
+func (o *operation) prunePolys(polys []*polyIn, margin float64) []*polyIn {
+	if o.opType != "intersection" && o.opType != "difference" {
+		return polys
+	}
+
+	bboxes := make([]bbox, len(polys))
+	for i := 0; i < len(polys); i++ {
+		bboxes[i] = polys[i].bbox
+	}
+
+	keep := make([]bool, len(polys))
+	switch o.opType {
+	case "intersection":
+		overlapped := make([]map[*multiPolyIn]bool, len(polys))
+		forEachOverlap(bboxes, margin, func(i, j int) {
+			mpI, mpJ := polys[i].multiPoly, polys[j].multiPoly
+			if mpI == mpJ {
+				return
+			}
+			if overlapped[i] == nil {
+				overlapped[i] = map[*multiPolyIn]bool{}
+			}
+			if overlapped[j] == nil {
+				overlapped[j] = map[*multiPolyIn]bool{}
+			}
+			overlapped[i][mpJ] = true
+			overlapped[j][mpI] = true
+		})
+		for i := 0; i < len(polys); i++ {
+			keep[i] = len(overlapped[i]) == o.numMultiPolys-1
+		}
+	case "difference":
+		forEachOverlap(bboxes, margin, func(i, j int) {
+			if polys[i].multiPoly.isSubject != polys[j].multiPoly.isSubject {
+				keep[i], keep[j] = true, true
+			}
+		})
+		for i := 0; i < len(polys); i++ {
+			if polys[i].multiPoly.isSubject {
+				keep[i] = true
+			}
+		}
+	}
+
+	kept := make([]*polyIn, 0, len(polys))
+	for i := 0; i < len(polys); i++ {
+		if keep[i] {
+			kept = append(kept, polys[i])
+		}
+	}
+	return kept
+}
+
+// groupPolys splits polygons into groups whose bboxes, grown by margin,
+// overlap one another, directly or through other members. Members of
+// different groups can't interact. Groups are ordered by their first
+// member, members by their order in polys.
+func groupPolys(polys []*polyIn, margin float64) [][]*polyIn {
+	parents := make([]int, len(polys))
+	for i := 0; i < len(parents); i++ {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	bboxes := make([]bbox, len(polys))
+	for i := 0; i < len(polys); i++ {
+		bboxes[i] = polys[i].bbox
+	}
+	forEachOverlap(bboxes, margin, func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri < rj {
+			parents[rj] = ri
+		} else if rj < ri {
+			parents[ri] = rj
+		}
+	})
+
+	groups := [][]*polyIn{}
+	groupIndexes := map[int]int{}
+	for i := 0; i < len(polys); i++ {
+		root := find(i)
+		g, ok := groupIndexes[root]
+		if !ok {
+			g = len(groups)
+			groupIndexes[root] = g
+			groups = append(groups, []*polyIn{})
+		}
+		groups[g] = append(groups[g], polys[i])
+	}
+	return groups
+}
+
+// contributes reports whether sweeping a group of polygons on its own can
+// add anything to the result.
+func (o *operation) contributes(group []*polyIn) bool {
+	switch o.opType {
+	case "intersection":
+		// every input needs to be there
+		multiPolys := map[*multiPolyIn]bool{}
+		for i := 0; i < len(group); i++ {
+			multiPolys[group[i].multiPoly] = true
+		}
+		return len(multiPolys) == o.numMultiPolys
+	case "difference":
+		// the subject needs to be there
+		for i := 0; i < len(group); i++ {
+			if group[i].multiPoly.isSubject {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// sweepGroups sweeps each group of polygons on its own operation, on the
+// group workers if there are any, returning the segments of each.
+func (o *operation) sweepGroups(groups [][]*polyIn) ([][]*segment, error) {
+	ops := make([]*operation, len(groups))
+	for i := 0; i < len(groups); i++ {
+		ops[i] = o.groupOperation(groups[i], i, len(groups))
+	}
+
+	results := make([][]*segment, len(groups))
+	errs := make([]error, len(groups))
+	if o.opts.groupWorkers > 1 {
+		jobs := make(chan int)
+		wg := sync.WaitGroup{}
+		for w := 0; w < o.opts.groupWorkers && w < len(groups); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					results[i], errs[i] = ops[i].sweepPolys(groups[i])
+				}
+			}()
+		}
+		for i := 0; i < len(groups); i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	} else {
+		for i := 0; i < len(groups); i++ {
+			results[i], errs[i] = ops[i].sweepPolys(groups[i])
+			if errs[i] != nil {
+				break
+			}
+		}
+	}
+
+	for i := 0; i < len(groups); i++ {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if ops[i].segmentID > o.segmentID {
+			o.segmentID = ops[i].segmentID
+		}
+	}
+	return results, nil
+}
+
+// mergeSegments merges the segments swept for each group into the order a
+// single sweep over all of them would have produced, by left endpoint.
+func mergeSegments(groupSegments [][]*segment) []*segment {
+	if len(groupSegments) == 1 {
+		return groupSegments[0]
+	}
+	total := 0
+	for i := 0; i < len(groupSegments); i++ {
+		total += len(groupSegments[i])
+	}
+	h := &segmentHeads{}
+	for i := 0; i < len(groupSegments); i++ {
+		if len(groupSegments[i]) > 0 {
+			h.lists = append(h.lists, groupSegments[i])
+		}
+	}
+	heap.Init(h)
+	segments := make([]*segment, 0, total)
+	for h.Len() > 0 {
+		list := h.lists[0]
+		segments = append(segments, list[0])
+		if len(list) > 1 {
+			h.lists[0] = list[1:]
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return segments
+}
+
+// segmentHeads is a heap of segment lists, ordered by their first segment.
+type segmentHeads struct {
+	lists [][]*segment
+}
+
+func (h *segmentHeads) Len() int { return len(h.lists) }
+
+func (h *segmentHeads) Less(i, j int) bool {
+	return sweepEventCompare(h.lists[i][0].leftSE, h.lists[j][0].leftSE) < 0
+}
+
+func (h *segmentHeads) Swap(i, j int) { h.lists[i], h.lists[j] = h.lists[j], h.lists[i] }
+
+func (h *segmentHeads) Push(x interface{}) { h.lists = append(h.lists, x.([]*segment)) }
+
+func (h *segmentHeads) Pop() interface{} {
+	last := h.lists[len(h.lists)-1]
+	h.lists = h.lists[:len(h.lists)-1]
+	return last
+}
+
+// groupOperation sets up an operation like o to sweep the i-th of n groups
+// of polygons on its own. It rounds with a fork of o's rounder, so that it
+// rounds just like o would however the groups are swept, and numbers new
+// segments i+1, i+1+n, i+1+2n... on from o's, so that no two groups hand
+// out the same ID. The group's segments are moved over to it.
+func (o *operation) groupOperation(polys []*polyIn, i, n int) *operation {
+	g := newOperation(o.opType)
+	g.opts = o.opts
+	g.nesting = o.nesting
+	g.numMultiPolys = o.numMultiPolys
+	g.segmentID = o.segmentID + i + 1 - n
+	g.segmentIDStep = n
+	g.hotPixels = o.hotPixels
+	g.origin = o.origin
+	g.scope = o.scope
+	g.rounder = o.rounder.fork(g.alloc())
+	for j := 0; j < len(polys); j++ {
+		sweepEvents := polys[j].getSweepEvents()
+		for k := 0; k < len(sweepEvents); k++ {
+			sweepEvents[k].segment.op = g
+		}
+	}
+	return g
+}
@@ -0,0 +1,152 @@
+package polygol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func square(x, y, size float64) Geom {
+	return Geom{{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}}}
+}
+
+func TestGroupPolys(t *testing.T) {
+	t.Parallel()
+
+	o := newOperation("union")
+	multiPolys, err := o.geomsToMultiPolys(square(0, 0, 2), []Geom{
+		square(10, 10, 2),
+		square(1, 1, 2),
+		square(12, 12, 2), // touching the second
+		square(4, 2, 1),
+		square(2.5, 2.5, 2), // bridging the first and the fifth
+	})
+	terr(t, err)
+	polys := []*polyIn{}
+	for i := 0; i < len(multiPolys); i++ {
+		polys = append(polys, multiPolys[i].polys...)
+	}
+
+	groups := groupPolys(polys, 0)
+	expect(t, len(groups) == 2)
+	expect(t, len(groups[0]) == 4)
+	expect(t, groups[0][0] == polys[0])
+	expect(t, groups[0][1] == polys[2])
+	expect(t, groups[0][2] == polys[4])
+	expect(t, groups[0][3] == polys[5])
+	expect(t, len(groups[1]) == 2)
+	expect(t, groups[1][0] == polys[1])
+	expect(t, groups[1][1] == polys[3])
+
+	// a margin joins bboxes that are close
+	groups = groupPolys(polys[:2], 4)
+	expect(t, len(groups) == 1)
+}
+
+func TestPrunePolys(t *testing.T) {
+	t.Parallel()
+
+	subject := Geom{square(0, 0, 2)[0], square(10, 0, 2)[0]}
+	clip := Geom{square(1, 1, 2)[0], square(20, 0, 2)[0]}
+	other := Geom{square(1.5, 0, 1)[0], square(10, 1, 2)[0]}
+
+	polysOf := func(o *operation, more ...Geom) []*polyIn {
+		multiPolys, err := o.geomsToMultiPolys(subject, more)
+		terr(t, err)
+		o.numMultiPolys = len(multiPolys)
+		polys := []*polyIn{}
+		for i := 0; i < len(multiPolys); i++ {
+			polys = append(polys, multiPolys[i].polys...)
+		}
+		return polys
+	}
+
+	t.Run("difference", func(t *testing.T) {
+		t.Parallel()
+		o := newOperation("difference")
+		polys := polysOf(o, clip)
+		kept := o.prunePolys(polys, 0)
+		// subject polygons are all kept, the far clipping polygon isn't
+		expect(t, len(kept) == 3)
+		expect(t, kept[0] == polys[0] && kept[1] == polys[1] && kept[2] == polys[2])
+	})
+
+	t.Run("intersection", func(t *testing.T) {
+		t.Parallel()
+		o := newOperation("intersection")
+		polys := polysOf(o, clip, other)
+		kept := o.prunePolys(polys, 0)
+		// only the polygons around the origin overlap all three inputs
+		expect(t, len(kept) == 3)
+		expect(t, kept[0] == polys[0] && kept[1] == polys[2] && kept[2] == polys[4])
+	})
+
+	t.Run("union", func(t *testing.T) {
+		t.Parallel()
+		o := newOperation("union")
+		polys := polysOf(o, clip)
+		expect(t, len(o.prunePolys(polys, 0)) == len(polys))
+	})
+}
+
+func TestSweepGroups(t *testing.T) {
+	t.Parallel()
+
+	geoms := []Geom{}
+	for i := 0; i < 6; i++ {
+		x := float64(i) * 10
+		geoms = append(geoms, square(x, 0, 2), square(x+1, 1, 2))
+	}
+
+	t.Run("parallel-matches-serial", func(t *testing.T) {
+		t.Parallel()
+		for _, op := range []Op{OpUnion, OpXOR, OpDifference} {
+			serial, err := Run(op, geoms[0], geoms[1:]...)
+			terr(t, err)
+			parallel, err := New(WithGroupWorkers(4)).Run(op, geoms[0], geoms[1:]...)
+			terr(t, err)
+			expect(t, reflect.DeepEqual(serial.Geom, parallel.Geom))
+			expect(t, reflect.DeepEqual(serial.Vertices, parallel.Vertices))
+		}
+		union, err := New(WithGroupWorkers(4)).Union(geoms[0], geoms[1:]...)
+		terr(t, err)
+		expect(t, len(union) == 6)
+	})
+
+	t.Run("near-coordinates", func(t *testing.T) {
+		t.Parallel()
+		// the square's edge crosses the triangle at y = 1.125, a hair off
+		// the bottom of a square in another group
+		near := []Geom{
+			square(0, 0, 2),
+			{{{{-1, 1}, {3, 1.5}, {-1, 3}, {-1, 1}}}},
+			square(100, 1.125+1e-13, 2),
+		}
+		serial, err := Union(near[0], near[1:]...)
+		terr(t, err)
+		parallel, err := New(WithGroupWorkers(2)).Union(near[0], near[1:]...)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(serial, parallel))
+		expect(t, serial[0][0][1][1] == 1.125+1e-13)
+	})
+
+	t.Run("unique-segment-ids", func(t *testing.T) {
+		t.Parallel()
+		for _, workers := range []int{1, 4} {
+			o := New(WithGroupWorkers(workers)).newOperation("union")
+			segments, _, err := o.sweepSegments(geoms[0], geoms[1:]...)
+			terr(t, err)
+			ids := map[int]bool{}
+			for _, seg := range segments {
+				expect(t, !ids[seg.id])
+				ids[seg.id] = true
+			}
+		}
+	})
+
+	t.Run("disjoint-intersection", func(t *testing.T) {
+		t.Parallel()
+		result, err := Intersection(geoms[0], geoms[1], geoms[2])
+		terr(t, err)
+		expect(t, len(result) == 0)
+	})
+}
@@ -0,0 +1,59 @@
+package polygol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intSquare(x, y, size int64) [][][]int64 {
+	return [][][]int64{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}}
+}
+
+func TestRunInt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("overlapping-squares", func(t *testing.T) {
+		t.Parallel()
+		result, err := RunInt(OpIntersection, IntGeom{intSquare(0, 0, 4)}, IntGeom{intSquare(2, 2, 4)})
+		terr(t, err)
+		expect(t, len(result) == 1)
+		expect(t, len(result[0]) == 1)
+		ring := result[0][0]
+		expect(t, len(ring) == 5)
+		for i := 0; i < len(ring); i++ {
+			expect(t, ring[i][0] >= 2 && ring[i][0] <= 4)
+			expect(t, ring[i][1] >= 2 && ring[i][1] <= 4)
+		}
+	})
+
+	t.Run("crossing-off-the-grid", func(t *testing.T) {
+		t.Parallel()
+		// the diagonals cross at (1.5, 1.5), which gets snapped to (2, 2)
+		a := IntGeom{{{{0, 0}, {3, 3}, {0, 3}, {0, 0}}}}
+		b := IntGeom{{{{0, 3}, {3, 0}, {3, 3}, {0, 3}}}}
+		result, err := RunInt(OpUnion, a, b)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result, IntGeom{{{{0, 0}, {2, 2}, {3, 0}, {3, 3}, {0, 3}, {0, 0}}}}))
+		result, err = RunInt(OpIntersection, a, b)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result, IntGeom{{{{0, 3}, {2, 2}, {3, 3}, {0, 3}}}}))
+	})
+
+	t.Run("large-coordinates", func(t *testing.T) {
+		t.Parallel()
+		// neighbours a unit apart are far below the float tolerance at this
+		// magnitude, but stay apart in exact mode
+		const o = int64(1) << 50
+		result, err := RunInt(OpUnion, IntGeom{intSquare(o, o, 1)}, IntGeom{intSquare(o+2, o, 1)})
+		terr(t, err)
+		expect(t, len(result) == 2)
+		expect(t, result[0][0][0][0] == o)
+		expect(t, result[1][0][0][0] == o+2)
+	})
+
+	t.Run("out-of-range", func(t *testing.T) {
+		t.Parallel()
+		_, err := RunInt(OpUnion, IntGeom{intSquare(1<<60, 0, 1)})
+		expect(t, err != nil)
+	})
+}
@@ -0,0 +1,84 @@
+package polygol
+
+import "sort"
+
+// Normalize returns a copy of geom in canonical order, so that equal results
+// compare equal regardless of input order. Each ring starts at its leftmost
+// vertex (lowest on ties), holes are sorted within their polygon and polygons
+// are sorted by their exterior ring. Ring orientation and closure are kept.
+func Normalize(geom Geom) Geom {
+	out := make(Geom, 0, len(geom))
+	for i := 0; i < len(geom); i++ {
+		poly := make([][][]float64, 0, len(geom[i]))
+		for j := 0; j < len(geom[i]); j++ {
+			poly = append(poly, normalizeRing(geom[i][j]))
+		}
+		if len(poly) > 1 {
+			holes := poly[1:]
+			sort.SliceStable(holes, func(a, b int) bool {
+				return compareRings(holes[a], holes[b]) < 0
+			})
+		}
+		out = append(out, poly)
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		if len(out[a]) == 0 || len(out[b]) == 0 {
+			return len(out[a]) < len(out[b])
+		}
+		return compareRings(out[a][0], out[b][0]) < 0
+	})
+	return out
+}
+
+// normalizeRing copies a ring, rotating it to start at its leftmost vertex.
+func normalizeRing(ring [][]float64) [][]float64 {
+	n := len(ring)
+	isClosed := n > 1 && comparePositions(ring[0], ring[n-1]) == 0
+	if isClosed {
+		n--
+	}
+	if n == 0 {
+		return [][]float64{}
+	}
+	iStart := 0
+	for i := 1; i < n; i++ {
+		cmp := comparePositions(ring[i], ring[iStart])
+		// a ring touching itself visits its leftmost vertex more than once,
+		// so break ties by the vertices that follow
+		for k := 1; cmp == 0 && k < n; k++ {
+			cmp = comparePositions(ring[(i+k)%n], ring[(iStart+k)%n])
+		}
+		if cmp < 0 {
+			iStart = i
+		}
+	}
+	out := make([][]float64, 0, len(ring))
+	for i := 0; i < n; i++ {
+		out = append(out, append([]float64{}, ring[(iStart+i)%n]...))
+	}
+	if isClosed {
+		out = append(out, append([]float64{}, out[0]...))
+	}
+	return out
+}
+
+func compareRings(a, b [][]float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if cmp := comparePositions(a[i], b[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return len(a) - len(b)
+}
+
+func comparePositions(a, b []float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
@@ -0,0 +1,46 @@
+package polygol
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ring-start", func(t *testing.T) {
+		geom := Geom{{{{1, 0}, {1, 1}, {0, 1}, {0, 0}, {1, 0}}}}
+		expect(t, equalMultiPoly(Normalize(geom), Geom{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}))
+	})
+
+	t.Run("open-ring", func(t *testing.T) {
+		geom := Geom{{{{1, 1}, {0, 1}, {0, 0}, {1, 0}}}}
+		expect(t, equalMultiPoly(Normalize(geom), Geom{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}}))
+	})
+
+	t.Run("hole-and-poly-order", func(t *testing.T) {
+		outer := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+		holeA := [][]float64{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}}
+		holeB := [][]float64{{5, 5}, {5, 6}, {6, 6}, {6, 5}, {5, 5}}
+		other := [][]float64{{20, 0}, {21, 0}, {21, 1}, {20, 1}, {20, 0}}
+		geom := Geom{{other}, {outer, holeB, holeA}}
+		expect(t, equalMultiPoly(Normalize(geom), Geom{{outer, holeA, holeB}, {other}}))
+	})
+
+	t.Run("does-not-modify-input", func(t *testing.T) {
+		geom := Geom{{{{1, 0}, {1, 1}, {0, 1}, {0, 0}, {1, 0}}}}
+		Normalize(geom)
+		expect(t, geom[0][0][0][0] == 1)
+	})
+
+	t.Run("input-order-independent", func(t *testing.T) {
+		a := Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}
+		b := Geom{{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}}
+		c := Geom{{{{10, 10}, {11, 10}, {11, 11}, {10, 11}, {10, 10}}}}
+		p := New(WithNormalize())
+		result1, err := p.Union(a, b, c)
+		terr(t, err)
+		result2, err := p.Union(c, b, a)
+		terr(t, err)
+		expect(t, equalMultiPoly(result1, result2))
+	})
+}
@@ -2,10 +2,9 @@ package polygol
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"strconv"
-
-	splaytree "github.com/engelsjk/splay-tree"
 )
 
 var (
@@ -41,23 +40,181 @@ type operation struct {
 	opType        string
 	numMultiPolys int
 	segmentID     int
+	segmentIDStep int
+	nesting       Nesting
+	opts          options
+	hotPixels     *hotPixels
+	strategy      Strategy
+	origin        []float64
+
+	arena *arena
+	scope *arenaScope
 }
 
 func newOperation(opType string) *operation {
 	rounder := newPtRounder()
 	return &operation{
-		rounder: rounder,
-		opType:  opType,
+		rounder:       rounder,
+		opType:        opType,
+		segmentIDStep: 1,
 	}
 }
 
 func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
+	defer o.release()
+
+	ringsOut, passedPolys, err := o.sweep(geom, moreGeoms...)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.getGeom(ringsOut, passedPolys), nil
+}
+
+func (o *operation) runResult(geom Geom, moreGeoms ...Geom) (*Result, error) {
+	defer o.release()
+
+	// only plain geometry output can do without the sweep's ring links
+	o.opts.passThrough = false
+
+	ringsOut, _, err := o.sweep(geom, moreGeoms...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newResult(o.getGeom(ringsOut, nil), ringsOut)
+	result.Strategy = o.strategy
+	return result, nil
+}
+
+// getGeom compiles output rings, and the polygons passed through, into a
+// multipolygon.
+func (o *operation) getGeom(ringsOut []*ringOut, passedPolys []*polyOut) Geom {
+	result := newMultiPolyOut(ringsOut)
+	result.polys = append(result.polys, passedPolys...)
+
+	if o.opts.normalize {
+		return Normalize(result.getGeom())
+	}
+	return result.getGeom()
+}
+
+func (o *operation) runTree(geom Geom, moreGeoms ...Geom) (*PolyTree, error) {
+	defer o.release()
+
+	o.opts.passThrough = false
+
+	ringsOut, _, err := o.sweep(geom, moreGeoms...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPolyTree(ringsOut), nil
+}
+
+// sweep runs the sweep line over the inputs and collects the segments
+// kept by the operation into output rings, falling back to more robust
+// strategies on failure if asked to. Polygons passed through come back
+// on their own.
+func (o *operation) sweep(geom Geom, moreGeoms ...Geom) ([]*ringOut, []*polyOut, error) {
+
+	if o.opts.localOrigin {
+		o.origin = localOrigin(o.opts.precision, append([]Geom{geom}, moreGeoms...))
+	}
+	if o.origin != nil {
+		geom = translateGeom(geom, -o.origin[0], -o.origin[1])
+		translated := make([]Geom, 0, len(moreGeoms))
+		for i := 0; i < len(moreGeoms); i++ {
+			translated = append(translated, translateGeom(moreGeoms[i], -o.origin[0], -o.origin[1]))
+		}
+		moreGeoms = translated
+	}
+	if o.opts.snapInputs > 0 && len(moreGeoms) > 0 {
+		snapper := newSubjectSnapper(geom, o.opts.snapInputs)
+		snapped := make([]Geom, 0, len(moreGeoms))
+		for i := 0; i < len(moreGeoms); i++ {
+			snapped = append(snapped, snapper.snapGeom(moreGeoms[i]))
+		}
+		moreGeoms = snapped
+	}
+
+	ringsOut, passedPolys, err := o.sweepOnce(geom, moreGeoms...)
+	if err == nil || !o.opts.fallback {
+		return ringsOut, passedPolys, err
+	}
+	return o.sweepFallback(err, geom, moreGeoms)
+}
 
+func (o *operation) sweepOnce(geom Geom, moreGeoms ...Geom) ([]*ringOut, []*polyOut, error) {
+
+	segments, passedPolys, err := o.sweepSegments(geom, moreGeoms...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Collect and compile segments we're keeping into rings.
+	ringsOut, err := newRingOutFromSegments(segments)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ringsOut, passedPolys, nil
+}
+
+// sweepSegments runs the sweep line over the inputs and returns all the
+// segments it processed, in sweep line order, along with the polygons
+// passed through without sweeping.
+func (o *operation) sweepSegments(geom Geom, moreGeoms ...Geom) ([]*segment, []*polyOut, error) {
+
+	if err := checkFinite(geom); err != nil {
+		return nil, nil, err
+	}
+	for i := 0; i < len(moreGeoms); i++ {
+		if err := checkFinite(moreGeoms[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if o.opts.precision > 0 && o.hotPixels == nil {
+		hotPixels, err := o.findHotPixels(geom, moreGeoms)
+		if err != nil {
+			return nil, nil, err
+		}
+		o.hotPixels = hotPixels
+	}
+
+	mark := o.memoryMark()
+	for round := 1; ; round++ {
+		// the segments of the last round are done with
+		o.forgetMemory(mark)
+		segments, passedPolys, err := o.sweepInputs(geom, moreGeoms)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Snapped edges can cross away from the grid. Those crossings make
+		// new hot pixels, and another sweep routes the edges through them.
+		if o.hotPixels == nil || !o.hotPixels.add(segments) {
+			return segments, passedPolys, nil
+		}
+		if round == maxSnapRounds {
+			return nil, nil, newAlgorithmError(
+				`Snapped edges still cross off the grid after %d snap rounding sweeps.`,
+				maxSnapRounds)
+		}
+	}
+}
+
+// sweepInputs turns the inputs into segments, snapped to the hot pixels if
+// any, and sweeps them.
+func (o *operation) sweepInputs(geom Geom, moreGeoms []Geom) ([]*segment, []*polyOut, error) {
+
+	o.rounder.tolerance = o.opts.tolerance
+	o.rounder.snapDistance = o.opts.snapDistance
+	o.rounder.arena = o.alloc()
 	o.rounder.reset()
 
 	multiPolys, err := o.geomsToMultiPolys(geom, moreGeoms)
 	if err != nil {
-		return Geom{}, err
+		return nil, nil, err
 	}
 	o.numMultiPolys = len(multiPolys)
 
@@ -77,30 +234,71 @@ func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
 				multiPolys = append(multiPolys[:i], multiPolys[i+1:]...) // splice
 			}
 		}
-	// BBox optimization for intersection operation
-	// If we can find any pair of multipolygons whose bbox does not overlap,
-	// then the result will be empty.
-	case "intersection":
-		// TODO: this is O(n^2) in number of polygons. By sorting the bboxes,
-		//       it could be optimized to O(n * ln(n))
-		for i := 0; i < len(multiPolys); i++ {
-			mpA := multiPolys[i]
-			for j := i + 1; j < len(multiPolys); j++ {
-				if mpA.bbox.getBboxOverlap(multiPolys[i].bbox) == nil {
-					return Geom{}, nil
-				}
+	}
+
+	// Polygons whose bboxes are apart can't interact, so those that can't
+	// contribute are dropped and each group of the rest that can interact
+	// is swept on its own.
+	polys := []*polyIn{}
+	for i := 0; i < len(multiPolys); i++ {
+		polys = append(polys, multiPolys[i].polys...)
+	}
+	polys = o.prunePolys(polys, o.opts.snapDistance)
+	passedPolys := []*polyOut{}
+	groups := [][]*polyIn{}
+	// snapping moves the vertices of swept polygons, so a polygon can only
+	// go through as it is when nothing is snapped
+	passThrough := o.opts.passThrough && o.opType != "build" &&
+		o.opts.precision == 0 && o.opts.snapDistance == 0 && o.opts.snapInputs == 0
+	allGroups := groupPolys(polys, o.opts.snapDistance)
+	for i := 0; i < len(allGroups); i++ {
+		if !o.contributes(allGroups[i]) {
+			continue
+		}
+		// a polygon that doesn't interact with anything is part of the
+		// result as it is
+		if passThrough && len(allGroups[i]) == 1 {
+			if po := o.passThroughPoly(allGroups[i][0]); po != nil {
+				passedPolys = append(passedPolys, po)
 			}
+			continue
+		}
+		groups = append(groups, allGroups[i])
+	}
+
+	var groupSegments [][]*segment
+	if len(groups) > 1 {
+		groupSegments, err = o.sweepGroups(groups)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if len(groups) == 1 {
+		segments, err := o.sweepPolys(groups[0])
+		if err != nil {
+			return nil, nil, err
 		}
+		groupSegments = append(groupSegments, segments)
 	}
 
+	// Free some memory we don't need anymore.
+	o.rounder.reset()
+
+	return mergeSegments(groupSegments), passedPolys, nil
+}
+
+// sweepPolys passes the sweep line over the polygons, returning all the
+// segments it processed, in sweep line order.
+func (o *operation) sweepPolys(polys []*polyIn) ([]*segment, error) {
+
 	// Put segment endpoints in a priority queue.
 	// Should be sorted by x coordinate.
-	queue := splaytree.New(sweepEventCompare)
-	for i := 0; i < len(multiPolys); i++ {
-		sweepEvents := multiPolys[i].getSweepEvents()
+	queue := newSplayTree(sweepEventCompare)
+	queue.nodes = &o.alloc().eventNodes
+	for i := 0; i < len(polys); i++ {
+		sweepEvents := polys[i].getSweepEvents()
 		for j := 0; j < len(sweepEvents); j++ {
-			queue.Insert(sweepEvents[j])
-			if queue.Size() > polygolClippingMaxQueueSize {
+			queue.insert(sweepEvents[j])
+			if queue.size > polygolClippingMaxQueueSize {
 				// prevents an infinite loop, an otherwise common manifestation of bugs
 				return nil, fmt.Errorf(
 					`Infinite loop when putting segment endpoints in a priority queue (queue size too big). Try increasing POLYGOL_MAX_QUEUE_SIZE > %d.`,
@@ -108,19 +306,22 @@ func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
 			}
 		}
 	}
+	if err := o.checkMemory(); err != nil {
+		return nil, err
+	}
 
 	///////////////////////////////////////////////////////////////
 	///////////////////////////////////////////////////////////////
 
 	// Pass the sweep line over those endpoints.
-	sweepLine := newSweepLine(queue, nil)
-	prevQueueSize := queue.Size()
-	node := queue.Pop()
-	i := 0
+	sweepLine := newSweepLine(queue)
+	sweepLine.tree.nodes = &o.alloc().segmentNodes
+	prevQueueSize := queue.size
+	node := queue.pop()
 	for node != nil {
 
-		evt := node.Item().(*sweepEvent)
-		if queue.Size() == prevQueueSize {
+		evt := node.item
+		if queue.size == prevQueueSize {
 
 			// prevents an infinite loop, an otherwise common manifestation of bugs
 			seg := evt.segment
@@ -128,7 +329,7 @@ func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
 			if evt.isLeft {
 				dir = "left"
 			}
-			return nil, fmt.Errorf(`Unable to pop() %s SweepEvent [%f, %f]
+			return nil, newAlgorithmError(`Unable to pop() %s SweepEvent [%f, %f]
 			from segment #%d [%f, %f] -> [%f, %f] from queue. Please file a bug report.`,
 				dir,
 				evt.point.x, evt.point.y,
@@ -137,7 +338,7 @@ func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
 				seg.rightSE.point.x, seg.rightSE.point.y)
 		}
 
-		if queue.Size() > polygolClippingMaxQueueSize {
+		if queue.size > polygolClippingMaxQueueSize {
 			// prevents an infinite loop, an otherwise common manifestation of bugs
 			return nil, fmt.Errorf(
 				`Infinite loop when passing sweep line over endspoints (queue size too big). Try increasing POLYGOL_MAX_QUEUE_SIZE > %d.`,
@@ -151,6 +352,10 @@ func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
 				polygolClippingMaxSweepLineSegments)
 		}
 
+		if err := o.checkMemory(); err != nil {
+			return nil, err
+		}
+
 		newEvents, err := sweepLine.process(evt)
 		if err != nil {
 			return nil, err
@@ -159,26 +364,43 @@ func (o *operation) run(geom Geom, moreGeoms ...Geom) (Geom, error) {
 		for i := 0; i < len(newEvents); i++ {
 			evt := newEvents[i]
 			if evt.consumedBy == nil {
-				queue.Insert(evt)
+				queue.insert(evt)
 			}
 		}
-		prevQueueSize = queue.Size()
-		node = queue.Pop()
-		i++
+		prevQueueSize = queue.size
+		node = queue.pop()
 	}
 
-	// Free some memory we don't need anymore.
-	o.rounder.reset()
+	return sweepLine.segments, nil
+}
 
-	// Collect and compile segments we're keeping into a multipolygon.
-	ringsOut, err := newRingOutFromSegments(sweepLine.segments)
-	if err != nil {
-		return nil, err
+// round turns a coordinate pair into a point. Nearby coordinates are
+// normally rounded together, in exact mode they're snapped to integers.
+func (o *operation) round(x, y float64) *point {
+	if o.opts.exact {
+		return o.alloc().newPoint(math.Round(x), math.Round(y))
 	}
+	return o.rounder.round(x, y)
+}
 
-	result := newMultiPolyOut(ringsOut)
-
-	return result.getGeom(), nil
+// comparePoints orders points for the sweep, with or without tolerance.
+func (o *operation) comparePoints(a, b *point) int {
+	if !o.opts.exact {
+		return sweepEventComparePoints(a, b, o.opts.tolerance)
+	}
+	if a.x != b.x {
+		if a.x < b.x {
+			return -1
+		}
+		return 1
+	}
+	if a.y != b.y {
+		if a.y < b.y {
+			return -1
+		}
+		return 1
+	}
+	return 0
 }
 
 func (o *operation) geomsToMultiPolys(geom Geom, moreGeoms []Geom) ([]*multiPolyIn, error) {
@@ -193,6 +415,7 @@ func (o *operation) geomsToMultiPolys(geom Geom, moreGeoms []Geom) ([]*multiPoly
 		if err != nil {
 			continue
 		}
+		multiPoly.index = i + 1
 		multiPolys = append(multiPolys, multiPoly)
 	}
 	return multiPolys, nil
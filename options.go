@@ -0,0 +1,205 @@
+package polygol
+
+// Option configures a Polygol instance.
+type Option func(*options)
+
+type options struct {
+	winding      Winding
+	openRings    bool
+	vertices     Vertices
+	normalize    bool
+	precision    float64
+	robust       bool
+	exact        bool
+	fallback     bool
+	localOrigin  bool
+	tolerance    Tolerance
+	snapDistance float64
+	snapInputs   float64
+	minArea      float64
+	minThinness  float64
+	groupWorkers int
+	passThrough  bool
+	pooling      bool
+	maxMemory    int64
+}
+
+// Winding selects the orientation convention of output rings.
+type Winding int
+
+const (
+	// WindingRFC7946 emits counter-clockwise exterior rings and clockwise
+	// holes, as required by GeoJSON.
+	WindingRFC7946 Winding = iota
+	// WindingESRI emits clockwise exterior rings and counter-clockwise
+	// holes, as used by shapefiles.
+	WindingESRI
+)
+
+// WithWinding sets the orientation convention of output rings.
+func WithWinding(winding Winding) Option {
+	return func(o *options) {
+		o.winding = winding
+	}
+}
+
+// WithOpenRings drops the repeated closing point from output rings.
+func WithOpenRings() Option {
+	return func(o *options) {
+		o.openRings = true
+	}
+}
+
+// Vertices selects which vertices along straight runs are kept in output rings.
+type Vertices int
+
+const (
+	// VerticesSimplified drops every vertex where the ring runs straight.
+	VerticesSimplified Vertices = iota
+	// VerticesInput keeps original input vertices even where the ring runs
+	// straight, dropping only intersection points.
+	VerticesInput
+	// VerticesAll keeps input vertices and intersection points alike.
+	VerticesAll
+)
+
+// WithVertices sets which vertices along straight runs are kept.
+func WithVertices(vertices Vertices) Option {
+	return func(o *options) {
+		o.vertices = vertices
+	}
+}
+
+// WithNormalize puts results in canonical order, see Normalize.
+func WithNormalize() Option {
+	return func(o *options) {
+		o.normalize = true
+	}
+}
+
+// WithPrecision snap rounds input vertices and intersection points to a
+// grid with the given cell size, so every output vertex lies on the grid.
+// Edges passing through the cell of a vertex or intersection are routed
+// through its center (hot pixel snap rounding), and the sweep runs on the
+// routed edges, adding the cells of any crossings between them, so that
+// snapping doesn't make edges cross or collapse. Crossings still off the
+// grid after a few sweeps fail the operation, see WithFallback.
+func WithPrecision(gridSize float64) Option {
+	return func(o *options) {
+		o.precision = gridSize
+	}
+}
+
+// WithRobustPredicates decides every side-of-line and angle question with
+// exact orientation predicates instead of epsilon comparisons. It costs
+// some speed, but avoids failures on nearly degenerate inputs.
+func WithRobustPredicates() Option {
+	return func(o *options) {
+		o.robust = true
+	}
+}
+
+// WithFallback retries an operation that fails on an internal error with
+// progressively more robust strategies, see Strategy. Run reports which
+// strategy succeeded.
+func WithFallback() Option {
+	return func(o *options) {
+		o.fallback = true
+	}
+}
+
+// WithLocalOrigin translates the inputs so they're centered on the origin
+// before sweeping, and the results back afterwards. Tolerances then scale
+// with the extent of the inputs rather than their distance from the
+// origin, which helps with large projected coordinates.
+func WithLocalOrigin() Option {
+	return func(o *options) {
+		o.localOrigin = true
+	}
+}
+
+// WithTolerance sets when coordinates are close enough to be merged into
+// one, see Tolerance. It has no effect with WithRobustPredicates on the
+// side-of-line tests, which are exact.
+func WithTolerance(tolerance Tolerance) Option {
+	return func(o *options) {
+		o.tolerance = tolerance
+	}
+}
+
+// WithSnapDistance merges vertices and intersection points that lie within
+// distance of one another, in any direction: each is moved onto the nearest
+// of those seen before it within distance, if any. By default x and y are
+// rounded separately, so only nearly equal coordinates are merged.
+func WithSnapDistance(distance float64) Option {
+	return func(o *options) {
+		o.snapDistance = distance
+	}
+}
+
+// WithSnapToSubject snaps the vertices of the clipping geometries onto
+// vertices or edges of the subject within tolerance before the operation,
+// and splits their edges at subject vertices within tolerance. Boundaries
+// that nearly coincide then coincide exactly, and don't leave slivers.
+// Result vertex indexes within clipping geometries refer to the snapped rings.
+func WithSnapToSubject(tolerance float64) Option {
+	return func(o *options) {
+		o.snapInputs = tolerance
+	}
+}
+
+// WithMinArea drops output polygons and holes whose ring encloses less
+// than the given area. Polygons within a dropped hole go with it.
+func WithMinArea(area float64) Option {
+	return func(o *options) {
+		o.minArea = area
+	}
+}
+
+// WithMinThinness drops output polygons and holes whose ring is a sliver:
+// its area divided by its perimeter squared is below ratio. That ratio is
+// 1/16 for a square and at most about 0.08, for a circle. As with
+// WithMinArea, polygons within a dropped hole are dropped too.
+func WithMinThinness(ratio float64) Option {
+	return func(o *options) {
+		o.minThinness = ratio
+	}
+}
+
+// WithGroupWorkers sweeps groups of inputs that can't interact, because
+// their bboxes are apart, concurrently on up to workers goroutines.
+func WithGroupWorkers(workers int) Option {
+	return func(o *options) {
+		o.groupWorkers = workers
+	}
+}
+
+// WithPassThrough copies input polygons whose bboxes don't interact with
+// any other polygon straight to the result, for operations returning a
+// Geom, instead of sweeping them. Such polygons are assumed to be valid,
+// and come after the swept ones in the result. Snapping options turn it
+// off, as passed polygons would miss the grid the others are snapped to.
+func WithPassThrough() Option {
+	return func(o *options) {
+		o.passThrough = true
+	}
+}
+
+// WithPooling keeps the segments, sweep events and points an operation
+// allocates, and the slices hanging off them, in arenas that are handed on
+// to later operations of the same Polygol instance, cutting down on garbage
+// when running many operations.
+func WithPooling() Option {
+	return func(o *options) {
+		o.pooling = true
+	}
+}
+
+// WithMaxMemory fails operations once the segments, sweep events and points
+// they allocate take up more than the given number of bytes, an estimate
+// that leaves out slices hanging off them. Zero means no limit.
+func WithMaxMemory(bytes int64) Option {
+	return func(o *options) {
+		o.maxMemory = bytes
+	}
+}
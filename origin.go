@@ -0,0 +1,84 @@
+package polygol
+
+import "math"
+
+// localOrigin picks a point near the center of the inputs to translate them
+// to, or nil if there are no inputs. The origin is rounded to a multiple of
+// the snap rounding grid if there is one, else to a power of two near the
+// extent of the inputs, so that translating coordinates close to it is exact.
+func localOrigin(precision float64, geoms []Geom) []float64 {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := 0; i < len(geoms); i++ {
+		for j := 0; j < len(geoms[i]); j++ {
+			for k := 0; k < len(geoms[i][j]); k++ {
+				for l := 0; l < len(geoms[i][j][k]); l++ {
+					position := geoms[i][j][k][l]
+					if len(position) < 2 {
+						continue
+					}
+					minX, maxX = math.Min(minX, position[0]), math.Max(maxX, position[0])
+					minY, maxY = math.Min(minY, position[1]), math.Max(maxY, position[1])
+				}
+			}
+		}
+	}
+	if minX > maxX || math.IsInf(minX, 0) || math.IsInf(maxX, 0) ||
+		math.IsInf(minY, 0) || math.IsInf(maxY, 0) {
+		return nil
+	}
+
+	unit := precision
+	if unit <= 0 {
+		extent := math.Max(maxX-minX, maxY-minY)
+		if extent == 0 {
+			extent = math.Max(math.Abs(minX), math.Abs(minY))
+		}
+		if extent == 0 {
+			return nil
+		}
+		unit = math.Pow(2, math.Floor(math.Log2(extent)))
+	}
+	return []float64{
+		math.Round((minX+maxX)/2/unit) * unit,
+		math.Round((minY+maxY)/2/unit) * unit,
+	}
+}
+
+// translateGeom copies geom, moving it by dx and dy. Z and M values are kept.
+func translateGeom(geom Geom, dx, dy float64) Geom {
+	out := make(Geom, 0, len(geom))
+	for i := 0; i < len(geom); i++ {
+		poly := make([][][]float64, 0, len(geom[i]))
+		for j := 0; j < len(geom[i]); j++ {
+			ring := make([][]float64, 0, len(geom[i][j]))
+			for k := 0; k < len(geom[i][j]); k++ {
+				position := append([]float64{}, geom[i][j][k]...)
+				if len(position) >= 2 {
+					position[0] += dx
+					position[1] += dy
+				}
+				ring = append(ring, position)
+			}
+			poly = append(poly, ring)
+		}
+		out = append(out, poly)
+	}
+	return out
+}
+
+// outputXY maps a point of the ring to its output coordinates: snapped to
+// its hot pixel if snap rounding, and back from the local origin.
+func (ro *ringOut) outputXY(pt *point) (float64, float64) {
+	x, y := pt.x, pt.y
+	if ro.op == nil {
+		return x, y
+	}
+	if ro.op.hotPixels != nil {
+		x, y = ro.op.hotPixels.snap(x), ro.op.hotPixels.snap(y)
+	}
+	if ro.op.origin != nil {
+		x, y = x+ro.op.origin[0], y+ro.op.origin[1]
+	}
+	return x, y
+}
@@ -0,0 +1,56 @@
+package polygol
+
+import (
+	"testing"
+)
+
+func TestLocalOrigin(t *testing.T) {
+	t.Parallel()
+
+	geom := Geom{{{{500000, 4649776}, {500010, 4649776}, {500010, 4649786}, {500000, 4649776}}}}
+
+	origin := localOrigin(0, []Geom{geom})
+	expect(t, equalVector(origin, []float64{500008, 4649784}))
+
+	origin = localOrigin(5, []Geom{geom})
+	expect(t, equalVector(origin, []float64{500005, 4649780}))
+
+	expect(t, localOrigin(0, []Geom{{}}) == nil)
+}
+
+func TestTranslateGeom(t *testing.T) {
+	t.Parallel()
+
+	geom := Geom{{{{1, 2, 3}, {4, 5, 6}}}}
+	out := translateGeom(geom, -1, 1)
+	expect(t, equalVector(out[0][0][0], []float64{0, 3, 3}))
+	expect(t, equalVector(out[0][0][1], []float64{3, 6, 6}))
+	// the input is left alone
+	expect(t, equalVector(geom[0][0][0], []float64{1, 2, 3}))
+}
+
+func TestWithLocalOrigin(t *testing.T) {
+	t.Parallel()
+
+	// the gap between the squares is below the float tolerance at this
+	// distance from the origin, but not relative to their size
+	const o = 1e7
+	a := Geom{{{{o, o}, {o + 1, o}, {o + 1, o + 1}, {o, o + 1}, {o, o}}}}
+	b := Geom{{{{o + 1 + 1e-5, o}, {o + 2, o}, {o + 2, o + 1}, {o + 1 + 1e-5, o + 1}, {o + 1 + 1e-5, o}}}}
+
+	union, err := Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 1)
+
+	union, err = New(WithLocalOrigin()).Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 2)
+	expect(t, equalVector(union[0][0][0], []float64{o, o}))
+	expect(t, equalVector(union[1][0][0], []float64{o + 1 + 1e-5, o}))
+
+	result, err := New(WithLocalOrigin()).Run(OpUnion, a, b)
+	terr(t, err)
+	expect(t, len(result.Vertices[1][0][0].Sources) == 1)
+	expect(t, result.Vertices[1][0][0].Sources[0].Input == 1)
+	expect(t, len(result.Edges[0][0][0].Inputs) == 1)
+}
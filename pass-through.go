@@ -0,0 +1,55 @@
+package polygol
+
+// passThroughPoly builds the output polygon for an input polygon that
+// doesn't interact with anything else, straight from its rings rather than
+// from a sweep. The polygon is assumed to be valid.
+func (o *operation) passThroughPoly(pi *polyIn) *polyOut {
+	exteriorRing := o.passThroughRing(pi.exteriorRing, true)
+	if exteriorRing == nil {
+		return nil
+	}
+	po := newPolyOut(exteriorRing)
+	for i := 0; i < len(pi.interiorRings); i++ {
+		ring := o.passThroughRing(pi.interiorRings[i], false)
+		if ring == nil {
+			continue
+		}
+		ring.enclosingRing = exteriorRing
+		po.addInterior(ring)
+	}
+	return po
+}
+
+// passThroughRing turns an input ring into an output ring, with its events
+// in counter-clockwise order like those of rings found by the sweep.
+func (o *operation) passThroughRing(ri *ringIn, isExterior bool) *ringOut {
+	if len(ri.segments) < 3 {
+		return nil
+	}
+	events := make([]*sweepEvent, 0, len(ri.segments)+1)
+	for i := 0; i < len(ri.segments); i++ {
+		seg := ri.segments[i]
+		if seg.windings[0] > 0 {
+			events = append(events, seg.leftSE)
+		} else {
+			events = append(events, seg.rightSE)
+		}
+	}
+	events = append(events, events[len(events)-1].otherSE)
+
+	area := 0.0
+	for i := 0; i < len(events)-1; i++ {
+		a, b := events[i].point, events[i+1].point
+		area += a.x*b.y - b.x*a.y
+	}
+	if area < 0 {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+
+	ro := newRingOut(events)
+	ro.forceExteriorRing = true
+	ro.isExteriorRing = isExterior
+	return ro
+}
@@ -0,0 +1,98 @@
+package polygol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithPassThrough(t *testing.T) {
+	t.Parallel()
+
+	// clockwise, with a counter-clockwise hole and a redundant point
+	building := Geom{{
+		{{0, 0}, {0, 4}, {4, 4}, {4, 2}, {4, 0}, {0, 0}},
+		{{1, 1}, {2, 1}, {2, 2}, {1, 2}, {1, 1}},
+	}}
+	// overlapping each other, but not the building
+	a := square(10, 0, 2)
+	b := square(11, 1, 2)
+
+	expected, err := Union(building, a, b)
+	terr(t, err)
+
+	t.Run("union", func(t *testing.T) {
+		t.Parallel()
+		result, err := New(WithPassThrough()).Union(building, a, b)
+		terr(t, err)
+		expect(t, len(result) == 2)
+		// the swept polygons come first
+		expect(t, reflect.DeepEqual(result[0], expected[1]))
+		expect(t, reflect.DeepEqual(result[1], expected[0]))
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		t.Parallel()
+		for _, op := range []Op{OpUnion, OpXOR} {
+			want, err := New(WithNormalize()).Tree(op, building, a, b)
+			terr(t, err)
+			p := New(WithNormalize(), WithPassThrough())
+			var result Geom
+			if op == OpUnion {
+				result, err = p.Union(building, a, b)
+			} else {
+				result, err = p.XOR(building, a, b)
+			}
+			terr(t, err)
+			expect(t, reflect.DeepEqual(result, Normalize(want.Geom())))
+		}
+	})
+
+	t.Run("winding and open rings", func(t *testing.T) {
+		t.Parallel()
+		result, err := New(WithPassThrough(), WithWinding(WindingESRI), WithOpenRings()).Union(building)
+		terr(t, err)
+		want, err := New(WithWinding(WindingESRI), WithOpenRings()).Union(building)
+		terr(t, err)
+		eq, err := EqualTopo(result, want, 0)
+		terr(t, err)
+		expect(t, eq)
+		expect(t, len(result[0][0]) == 4)
+		expect(t, reflect.DeepEqual(result[0][0][0], want[0][0][0]))
+		expect(t, reflect.DeepEqual(result[0][0][1], want[0][0][1]))
+	})
+
+	t.Run("intersection", func(t *testing.T) {
+		t.Parallel()
+		result, err := New(WithPassThrough()).Intersection(building, a, b)
+		terr(t, err)
+		expect(t, len(result) == 0)
+
+		result, err = New(WithPassThrough()).Intersection(building)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result, expected[:1]))
+	})
+
+	t.Run("difference", func(t *testing.T) {
+		t.Parallel()
+		result, err := New(WithPassThrough()).Difference(building, a)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result, expected[:1]))
+	})
+}
+
+func TestWithPassThroughSnapped(t *testing.T) {
+	t.Parallel()
+
+	// the hole snaps onto the exterior ring's bottom edge
+	poly := Geom{{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{1.2, 0.2}, {1.2, 5}, {5, 5}, {5, 0.2}, {1.2, 0.2}},
+	}}
+
+	want, err := New(WithPrecision(1)).Union(poly)
+	terr(t, err)
+	result, err := New(WithPrecision(1), WithPassThrough()).Union(poly)
+	terr(t, err)
+	expect(t, reflect.DeepEqual(result, want))
+	expect(t, snappedDefect(result) == "")
+}
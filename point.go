@@ -1,9 +1,17 @@
 package polygol
 
 type point struct {
-	x      float64
-	y      float64
-	events []*sweepEvent
+	x       float64
+	y       float64
+	events  []*sweepEvent
+	sources []vertexSource
+	zm      []float64
+}
+
+// vertexSource records an input ring vertex that a point came from.
+type vertexSource struct {
+	ring  *ringIn
+	index int
 }
 
 func newPoint(x, y float64) *point {
@@ -12,10 +20,56 @@ func newPoint(x, y float64) *point {
 	}
 }
 
-func (p point) xy() []float64 {
-	return []float64{p.x, p.y}
+func (p point) xy() vector {
+	return vector{p.x, p.y}
+}
+
+// position returns the coordinates of the point, including any Z/M values.
+func (p point) position() []float64 {
+	pos := make([]float64, 2, 2+len(p.zm))
+	pos[0], pos[1] = p.x, p.y
+	return append(pos, p.zm...)
+}
+
+// interpolateZM sets the Z/M values of a point lying on the line between
+// a and b by linear interpolation, if it doesn't have any already.
+func (p *point) interpolateZM(a, b *point) {
+	if p.zm != nil || a.zm == nil || len(a.zm) != len(b.zm) {
+		return
+	}
+	v := b.xy().sub(a.xy())
+	lenSq := dotProduct(v, v)
+	t := 0.0
+	if lenSq > 0 {
+		t = dotProduct(p.xy().sub(a.xy()), v) / lenSq
+	}
+	p.zm = make([]float64, len(a.zm))
+	for i := 0; i < len(a.zm); i++ {
+		p.zm[i] = a.zm[i] + t*(b.zm[i]-a.zm[i])
+	}
 }
 
 func (p point) equal(point point) bool {
 	return p.x == point.x && p.y == point.y
 }
+
+// isInput reports whether the point is an original input vertex,
+// as opposed to a computed intersection point.
+func (p point) isInput() bool {
+	return len(p.sources) > 0
+}
+
+func (p *point) addSources(sources []vertexSource) {
+	for i := 0; i < len(sources); i++ {
+		found := false
+		for j := 0; j < len(p.sources); j++ {
+			if p.sources[j] == sources[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.sources = append(p.sources, sources[i])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package polygol
+
+// PolyNode is an output ring along with its place in the ring hierarchy.
+// Exterior rings have hole children, holes have island children, and so on.
+type PolyNode struct {
+	Ring     [][]float64
+	IsHole   bool
+	Depth    int
+	Parent   *PolyNode
+	Children []*PolyNode
+}
+
+// PolyTree is the ring hierarchy of an operation result. Its children are
+// the outermost exterior rings.
+type PolyTree struct {
+	Children []*PolyNode
+}
+
+func newPolyTree(rings []*ringOut) *PolyTree {
+	pt := &PolyTree{Children: []*PolyNode{}}
+	nodes := make(map[*ringOut]*PolyNode, len(rings))
+
+	var getNode func(ring *ringOut) *PolyNode
+	getNode = func(ring *ringOut) *PolyNode {
+		if node, ok := nodes[ring]; ok {
+			return node
+		}
+		// rings within sliver holes are covered by the polygon around them
+		if ring.isInSliver() {
+			nodes[ring] = nil
+			return nil
+		}
+		var node *PolyNode
+		geom := ring.getGeom()
+		// rings that were all (within rounding error of angle calc) colinear
+		// points are skipped, their children are hung off the next ring up
+		if geom != nil {
+			node = &PolyNode{
+				Ring:     geom,
+				IsHole:   !ring.calcIsExteriorRing(),
+				Children: []*PolyNode{},
+			}
+		}
+		var parent *PolyNode
+		for enclosing := ring.getEnclosingRing(); enclosing != nil; enclosing = enclosing.getEnclosingRing() {
+			parent = getNode(enclosing)
+			if parent != nil {
+				break
+			}
+		}
+		if node == nil {
+			nodes[ring] = parent
+			return parent
+		}
+		if parent != nil {
+			node.Parent = parent
+			node.Depth = parent.Depth + 1
+			parent.Children = append(parent.Children, node)
+		} else {
+			pt.Children = append(pt.Children, node)
+		}
+		nodes[ring] = node
+		return node
+	}
+
+	for i := 0; i < len(rings); i++ {
+		getNode(rings[i])
+	}
+	return pt
+}
+
+// Geom flattens the tree back into a multipolygon, dropping the nesting of
+// exterior rings within holes.
+func (pt *PolyTree) Geom() Geom {
+	geom := Geom{}
+	var walk func(nodes []*PolyNode)
+	walk = func(nodes []*PolyNode) {
+		for i := 0; i < len(nodes); i++ {
+			node := nodes[i]
+			if node.IsHole {
+				walk(node.Children)
+				continue
+			}
+			poly := [][][]float64{node.Ring}
+			for j := 0; j < len(node.Children); j++ {
+				if node.Children[j].IsHole {
+					poly = append(poly, node.Children[j].Ring)
+				}
+			}
+			geom = append(geom, poly)
+			walk(node.Children)
+		}
+	}
+	walk(pt.Children)
+	return geom
+}
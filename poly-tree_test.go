@@ -0,0 +1,67 @@
+package polygol
+
+import (
+	"testing"
+)
+
+func TestPolyTree(t *testing.T) {
+	t.Parallel()
+
+	outer := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	hole := [][]float64{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}
+	island := [][]float64{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}
+	other := [][]float64{{20, 0}, {21, 0}, {21, 1}, {20, 1}, {20, 0}}
+
+	t.Run("island-in-hole", func(t *testing.T) {
+		tree, err := Tree(OpUnion, Geom{{outer, hole}}, Geom{{island}})
+		terr(t, err)
+
+		expect(t, len(tree.Children) == 1)
+		root := tree.Children[0]
+		expect(t, !root.IsHole)
+		expect(t, root.Depth == 0)
+		expect(t, root.Parent == nil)
+		expect(t, equalRing(root.Ring, outer))
+
+		expect(t, len(root.Children) == 1)
+		h := root.Children[0]
+		expect(t, h.IsHole)
+		expect(t, h.Depth == 1)
+		expect(t, h.Parent == root)
+		expect(t, equalRing(h.Ring, hole))
+
+		expect(t, len(h.Children) == 1)
+		i := h.Children[0]
+		expect(t, !i.IsHole)
+		expect(t, i.Depth == 2)
+		expect(t, i.Parent == h)
+		expect(t, len(i.Children) == 0)
+		expect(t, equalRing(i.Ring, island))
+
+		expect(t, equalMultiPoly(tree.Geom(), Geom{{outer, hole}, {island}}))
+	})
+
+	t.Run("disjoint", func(t *testing.T) {
+		tree, err := Tree(OpUnion, Geom{{outer}}, Geom{{other}})
+		terr(t, err)
+		expect(t, len(tree.Children) == 2)
+		expect(t, tree.Children[0].Depth == 0)
+		expect(t, tree.Children[1].Depth == 0)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		tree, err := Tree(OpIntersection, Geom{{outer}}, Geom{{other}})
+		terr(t, err)
+		expect(t, len(tree.Children) == 0)
+		expect(t, len(tree.Geom()) == 0)
+	})
+
+	t.Run("unknown-op", func(t *testing.T) {
+		_, err := Tree(Op("build"), Geom{{outer}})
+		expect(t, err != nil)
+		_, err = Run(Op("nope"), Geom{{outer}})
+		expect(t, err != nil)
+		_, err = RunInt(Op(""), IntGeom{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}})
+		expect(t, err != nil)
+	})
+}
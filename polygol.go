@@ -1,27 +1,110 @@
 package polygol
 
+import (
+	"fmt"
+	"sync"
+)
+
 type Geom [][][][]float64
 
-type Polygol struct{}
+type Polygol struct {
+	opts   options
+	arenas *sync.Pool
+}
+
+// Op names a Boolean operation.
+type Op string
 
-func New() *Polygol {
-	return &Polygol{}
+const (
+	OpUnion        Op = "union"
+	OpIntersection Op = "intersection"
+	OpDifference   Op = "difference"
+	OpXOR          Op = "xor"
+)
+
+// check fails for anything but the Op constants.
+func (op Op) check() error {
+	switch op {
+	case OpUnion, OpIntersection, OpDifference, OpXOR:
+		return nil
+	}
+	return fmt.Errorf(`Unknown operation %q.`, string(op))
+}
+
+// Nesting selects how BuildPolygons decides whether a ring is a shell or a hole.
+type Nesting int
+
+const (
+	// NestByContainment treats rings by nesting depth alone: rings at even
+	// depth are shells, rings at odd depth are holes. Orientation is ignored.
+	NestByContainment Nesting = iota
+	// NestByOrientation trusts ring orientation: a ring wound opposite to
+	// the ring enclosing it is a hole, as in shapefiles.
+	NestByOrientation
+)
+
+func New(opts ...Option) *Polygol {
+	p := &Polygol{}
+	for i := 0; i < len(opts); i++ {
+		opts[i](&p.opts)
+	}
+	if p.opts.pooling {
+		p.arenas = newArenaPool()
+	}
+	return p
+}
+
+func (p *Polygol) newOperation(opType string) *operation {
+	o := newOperation(opType)
+	o.opts = p.opts
+	o.scope = &arenaScope{pool: p.arenas}
+	return o
 }
 
 func (p *Polygol) Union(geom Geom, moreGeoms ...Geom) (Geom, error) {
-	return newOperation("union").run(geom, moreGeoms...)
+	return p.newOperation("union").run(geom, moreGeoms...)
 }
 
 func (p *Polygol) Intersection(geom Geom, moreGeoms ...Geom) (Geom, error) {
-	return newOperation("intersection").run(geom, moreGeoms...)
+	return p.newOperation("intersection").run(geom, moreGeoms...)
 }
 
 func (p *Polygol) Difference(geom Geom, moreGeoms ...Geom) (Geom, error) {
-	return newOperation("difference").run(geom, moreGeoms...)
+	return p.newOperation("difference").run(geom, moreGeoms...)
 }
 
 func (p *Polygol) XOR(geom Geom, moreGeoms ...Geom) (Geom, error) {
-	return newOperation("xor").run(geom, moreGeoms...)
+	return p.newOperation("xor").run(geom, moreGeoms...)
+}
+
+// Tree runs the operation and returns the result as a ring hierarchy,
+// preserving islands within holes.
+func (p *Polygol) Tree(op Op, geom Geom, moreGeoms ...Geom) (*PolyTree, error) {
+	if err := op.check(); err != nil {
+		return nil, err
+	}
+	return p.newOperation(string(op)).runTree(geom, moreGeoms...)
+}
+
+// Run runs the operation and returns the result along with the provenance
+// of its output vertices.
+func (p *Polygol) Run(op Op, geom Geom, moreGeoms ...Geom) (*Result, error) {
+	if err := op.check(); err != nil {
+		return nil, err
+	}
+	return p.newOperation(string(op)).runResult(geom, moreGeoms...)
+}
+
+// BuildPolygons nests a flat list of rings into polygons, assigning each hole
+// to the shell that encloses it.
+func (p *Polygol) BuildPolygons(rings [][][]float64, nesting Nesting) (Geom, error) {
+	geom := make(Geom, 0, len(rings))
+	for i := 0; i < len(rings); i++ {
+		geom = append(geom, [][][]float64{rings[i]})
+	}
+	op := p.newOperation("build")
+	op.nesting = nesting
+	return op.run(geom)
 }
 
 func Union(geom Geom, moreGeoms ...Geom) (Geom, error) {
@@ -39,3 +122,15 @@ func Difference(geom Geom, moreGeoms ...Geom) (Geom, error) {
 func XOR(geom Geom, moreGeoms ...Geom) (Geom, error) {
 	return New().XOR(geom, moreGeoms...)
 }
+
+func Tree(op Op, geom Geom, moreGeoms ...Geom) (*PolyTree, error) {
+	return New().Tree(op, geom, moreGeoms...)
+}
+
+func Run(op Op, geom Geom, moreGeoms ...Geom) (*Result, error) {
+	return New().Run(op, geom, moreGeoms...)
+}
+
+func BuildPolygons(rings [][][]float64, nesting Nesting) (Geom, error) {
+	return New().BuildPolygons(rings, nesting)
+}
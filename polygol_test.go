@@ -15,3 +15,42 @@ func terr(t *testing.T, err error) {
 		t.FailNow()
 	}
 }
+
+func TestBuildPolygons(t *testing.T) {
+	t.Parallel()
+
+	outer := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	innerCCW := [][]float64{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}
+	innerCW := [][]float64{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}
+	island := [][]float64{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}
+
+	t.Run("containment", func(t *testing.T) {
+		result, err := BuildPolygons([][][]float64{innerCCW, outer}, NestByContainment)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{outer, innerCW}}))
+	})
+
+	t.Run("containment-island-in-hole", func(t *testing.T) {
+		result, err := BuildPolygons([][][]float64{island, outer, innerCW}, NestByContainment)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{outer, innerCW}, {island}}))
+	})
+
+	t.Run("orientation-hole", func(t *testing.T) {
+		result, err := BuildPolygons([][][]float64{outer, innerCW}, NestByOrientation)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{outer, innerCW}}))
+	})
+
+	t.Run("orientation-same-winding", func(t *testing.T) {
+		result, err := BuildPolygons([][][]float64{outer, innerCCW}, NestByOrientation)
+		terr(t, err)
+		expect(t, equalMultiPoly(result, Geom{{outer}}))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := BuildPolygons([][][]float64{}, NestByContainment)
+		terr(t, err)
+		expect(t, len(result) == 0)
+	})
+}
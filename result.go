@@ -0,0 +1,169 @@
+package polygol
+
+import "sort"
+
+// Result is the outcome of an operation along with where each of its
+// output vertices and edges came from.
+type Result struct {
+	Geom Geom
+	// Vertices parallels Geom, with one entry per output vertex.
+	Vertices [][][]Vertex
+	// Edges parallels Geom, with one entry per output edge. Edge k runs
+	// from vertex k to vertex k+1, wrapping around for open rings.
+	Edges [][][]Edge
+	// Strategy is the strategy that produced the result, see WithFallback.
+	Strategy Strategy
+}
+
+// Edge describes the provenance of an output edge.
+type Edge struct {
+	// Inputs lists, in ascending order, the inputs whose boundary the edge
+	// lies on: 0 for the subject geom and i+1 for the i-th of moreGeoms.
+	Inputs []int
+}
+
+// Vertex describes the provenance of an output vertex.
+type Vertex struct {
+	// Sources lists the input vertices found at this position. It is empty
+	// for intersection points computed by the operation.
+	Sources []VertexSource
+}
+
+// IsIntersection reports whether the vertex was computed by the operation
+// rather than taken from the input.
+func (v Vertex) IsIntersection() bool {
+	return len(v.Sources) == 0
+}
+
+// VertexSource locates an input vertex: Input is 0 for the subject geom and
+// i+1 for the i-th of moreGeoms, Poly and Ring index into that geom and
+// Index is the position of the vertex within the ring.
+type VertexSource struct {
+	Input int
+	Poly  int
+	Ring  int
+	Index int
+}
+
+func newResult(geom Geom, rings []*ringOut) *Result {
+	// Linked events share a single point, so each output position maps
+	// to exactly one point.
+	points := map[[2]float64]*point{}
+	for i := 0; i < len(rings); i++ {
+		for j := 0; j < len(rings[i].events); j++ {
+			pt := rings[i].events[j].point
+			x, y := rings[i].outputXY(pt)
+			points[[2]float64{x, y}] = pt
+		}
+	}
+
+	// An output edge is likewise identified by its two end positions.
+	edges := map[[4]float64]Edge{}
+	for i := 0; i < len(rings); i++ {
+		rings[i].addEdges(edges)
+	}
+
+	r := &Result{
+		Geom:     geom,
+		Vertices: make([][][]Vertex, len(geom)),
+		Edges:    make([][][]Edge, len(geom)),
+	}
+	for i := 0; i < len(geom); i++ {
+		r.Vertices[i] = make([][]Vertex, len(geom[i]))
+		r.Edges[i] = make([][]Edge, len(geom[i]))
+		for j := 0; j < len(geom[i]); j++ {
+			ring := geom[i][j]
+			r.Vertices[i][j] = make([]Vertex, len(ring))
+			for k := 0; k < len(ring); k++ {
+				pt := points[[2]float64{ring[k][0], ring[k][1]}]
+				if pt == nil {
+					continue
+				}
+				r.Vertices[i][j][k] = newVertex(pt)
+			}
+
+			numEdges := len(ring)
+			if numEdges > 1 && ring[0][0] == ring[numEdges-1][0] && ring[0][1] == ring[numEdges-1][1] {
+				numEdges--
+			}
+			r.Edges[i][j] = make([]Edge, numEdges)
+			for k := 0; k < numEdges; k++ {
+				next := ring[(k+1)%len(ring)]
+				r.Edges[i][j][k] = edges[edgeKey(ring[k][0], ring[k][1], next[0], next[1])]
+			}
+		}
+	}
+	return r
+}
+
+// addEdges records the inputs of each edge of the output ring. An output
+// edge can span several segments when colinear points were dropped.
+func (ro *ringOut) addEdges(edges map[[4]float64]Edge) {
+	indexes := ro.getPointIndexes()
+	if indexes == nil {
+		return
+	}
+	numPoints := len(ro.events) - 1
+	for j := 0; j < len(indexes); j++ {
+		from := indexes[j]
+		to := indexes[(j+1)%len(indexes)]
+		steps := (to - from + numPoints) % numPoints
+		inputs := []int{}
+		for step := 0; step < steps; step++ {
+			i := (from + step) % numPoints
+			seg := ro.segmentBetween(ro.events[i].point, ro.events[i+1].point)
+			if seg == nil {
+				continue
+			}
+			for k := 0; k < len(seg.rings); k++ {
+				inputs = appendInput(inputs, seg.rings[k].poly.multiPoly.index)
+			}
+		}
+		sort.Ints(inputs)
+		x1, y1 := ro.outputXY(ro.events[from].point)
+		x2, y2 := ro.outputXY(ro.events[to].point)
+		edges[edgeKey(x1, y1, x2, y2)] = Edge{Inputs: inputs}
+	}
+}
+
+// segmentBetween finds the segment of the ring joining two of its points.
+func (ro *ringOut) segmentBetween(a, b *point) *segment {
+	for i := 0; i < len(a.events); i++ {
+		evt := a.events[i]
+		if evt.otherSE.point == b && evt.segment.ringOut == ro {
+			return evt.segment
+		}
+	}
+	return nil
+}
+
+func appendInput(inputs []int, input int) []int {
+	for i := 0; i < len(inputs); i++ {
+		if inputs[i] == input {
+			return inputs
+		}
+	}
+	return append(inputs, input)
+}
+
+// edgeKey identifies an edge by its end positions, regardless of direction.
+func edgeKey(x1, y1, x2, y2 float64) [4]float64 {
+	if x2 < x1 || (x2 == x1 && y2 < y1) {
+		x1, y1, x2, y2 = x2, y2, x1, y1
+	}
+	return [4]float64{x1, y1, x2, y2}
+}
+
+func newVertex(pt *point) Vertex {
+	v := Vertex{}
+	for i := 0; i < len(pt.sources); i++ {
+		source := pt.sources[i]
+		v.Sources = append(v.Sources, VertexSource{
+			Input: source.ring.poly.multiPoly.index,
+			Poly:  source.ring.poly.index,
+			Ring:  source.ring.index,
+			Index: source.index,
+		})
+	}
+	return v
+}
@@ -0,0 +1,178 @@
+package polygol
+
+import (
+	"testing"
+)
+
+func TestResultVertices(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}
+	b := Geom{{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}}
+
+	for _, p := range []*Polygol{New(), New(WithNormalize())} {
+		result, err := p.Run(OpUnion, a, b)
+		terr(t, err)
+
+		expect(t, equalMultiPoly(result.Geom, Geom{{{
+			{0, 0}, {2, 0}, {2, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 2}, {0, 2}, {0, 0},
+		}}}))
+		expect(t, len(result.Vertices) == 1)
+		expect(t, len(result.Vertices[0]) == 1)
+
+		vertices := result.Vertices[0][0]
+		expect(t, len(vertices) == 9)
+
+		expect(t, !vertices[0].IsIntersection())
+		expect(t, len(vertices[0].Sources) == 1)
+		expect(t, vertices[0].Sources[0] == VertexSource{Input: 0, Poly: 0, Ring: 0, Index: 0})
+
+		expect(t, vertices[2].IsIntersection())
+		expect(t, vertices[6].IsIntersection())
+
+		expect(t, !vertices[3].IsIntersection())
+		expect(t, vertices[3].Sources[0] == VertexSource{Input: 1, Poly: 0, Ring: 0, Index: 1})
+
+		expect(t, vertices[8].Sources[0] == VertexSource{Input: 0, Poly: 0, Ring: 0, Index: 0})
+	}
+}
+
+func TestResultVerticesShared(t *testing.T) {
+	t.Parallel()
+
+	// both inputs have a vertex at [2, 2], the second in its hole
+	a := Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}
+	b := Geom{
+		{{{5, 5}, {6, 5}, {6, 6}, {5, 6}, {5, 5}}},
+		{
+			{{-1, -1}, {4, -1}, {4, 4}, {-1, 4}, {-1, -1}},
+			{{2, 2}, {3, 2}, {3, 3}, {2, 3}, {2, 2}},
+		},
+	}
+
+	result, err := Run(OpIntersection, a, b)
+	terr(t, err)
+	expect(t, len(result.Geom) == 1)
+	found := false
+	for k, pos := range result.Geom[0][0] {
+		if pos[0] != 2 || pos[1] != 2 {
+			continue
+		}
+		found = true
+		sources := result.Vertices[0][0][k].Sources
+		expect(t, len(sources) == 2)
+		expect(t, sources[0] == VertexSource{Input: 0, Poly: 0, Ring: 0, Index: 2} ||
+			sources[1] == VertexSource{Input: 0, Poly: 0, Ring: 0, Index: 2})
+		expect(t, sources[0] == VertexSource{Input: 1, Poly: 1, Ring: 1, Index: 0} ||
+			sources[1] == VertexSource{Input: 1, Poly: 1, Ring: 1, Index: 0})
+	}
+	expect(t, found)
+}
+
+func TestResultEdges(t *testing.T) {
+	t.Parallel()
+
+	// the second square shares part of the right edge of the first
+	a := Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}
+	b := Geom{{{{2, 0}, {4, 0}, {4, 1}, {2, 1}, {2, 0}}}}
+
+	for _, p := range []*Polygol{New(), New(WithNormalize()), New(WithOpenRings())} {
+		result, err := p.Run(OpDifference, a, b)
+		terr(t, err)
+
+		expect(t, len(result.Edges) == 1)
+		expect(t, len(result.Edges[0]) == 1)
+		ring := result.Geom[0][0]
+		edges := result.Edges[0][0]
+		expect(t, len(edges) == 4)
+
+		for k, edge := range edges {
+			from := ring[k]
+			to := ring[(k+1)%len(ring)]
+			if from[0] == 2 && to[0] == 2 {
+				// right edge, from [2, 0] to [2, 2], half shared with b
+				expect(t, len(edge.Inputs) == 2)
+				expect(t, edge.Inputs[0] == 0 && edge.Inputs[1] == 1)
+			} else {
+				expect(t, len(edge.Inputs) == 1)
+				expect(t, edge.Inputs[0] == 0)
+			}
+		}
+	}
+}
+
+func TestResultEdgesClip(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}}
+	b := Geom{{{{2, -1}, {5, -1}, {5, 5}, {2, 5}, {2, -1}}}}
+
+	result, err := Run(OpDifference, a, b)
+	terr(t, err)
+	expect(t, equalMultiPoly(result.Geom, Geom{{{{0, 0}, {2, 0}, {2, 4}, {0, 4}, {0, 0}}}}))
+	edges := result.Edges[0][0]
+	expect(t, len(edges) == 4)
+	expect(t, edges[0].Inputs[0] == 0)
+	expect(t, len(edges[1].Inputs) == 1 && edges[1].Inputs[0] == 1)
+	expect(t, edges[2].Inputs[0] == 0)
+	expect(t, edges[3].Inputs[0] == 0)
+}
+
+func TestResultPrecision(t *testing.T) {
+	t.Parallel()
+
+	t.Run("input-vertices", func(t *testing.T) {
+		t.Parallel()
+		// output positions get snapped to the grid
+		a := Geom{{{{0.1, 0.1}, {2.1, 0.1}, {2.1, 2.1}, {0.1, 2.1}, {0.1, 0.1}}}}
+		b := Geom{{{{2.1, 0.1}, {4.1, 0.1}, {4.1, 1.1}, {2.1, 1.1}, {2.1, 0.1}}}}
+
+		result, err := New(WithPrecision(1)).Run(OpDifference, a, b)
+		terr(t, err)
+		expect(t, equalMultiPoly(result.Geom, Geom{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}))
+
+		vertices := result.Vertices[0][0]
+		expect(t, len(vertices) == 5)
+		expect(t, vertices[0].Sources[0] == VertexSource{Input: 0, Poly: 0, Ring: 0, Index: 0})
+		for k := 0; k < len(vertices); k++ {
+			expect(t, !vertices[k].IsIntersection())
+		}
+
+		edges := result.Edges[0][0]
+		expect(t, len(edges) == 4)
+		expect(t, len(edges[1].Inputs) == 2)
+		expect(t, edges[0].Inputs[0] == 0)
+		expect(t, edges[2].Inputs[0] == 0)
+		expect(t, edges[3].Inputs[0] == 0)
+	})
+
+	t.Run("off-grid-intersections", func(t *testing.T) {
+		t.Parallel()
+		// the sweep finds intersection points between grid nodes, which
+		// only get snapped on output
+		a := Geom{{{{4.5, 1}, {4.5, 2}, {0, 3}, {4.5, 1}}}}
+		b := Geom{{{{3.75, 0}, {1.25, 2.5}, {3.75, 4.5}, {3.75, 0}}}}
+
+		result, err := New(WithPrecision(1)).Run(OpUnion, a, b)
+		terr(t, err)
+		expect(t, len(result.Geom) == 1)
+
+		sources := map[[2]float64]VertexSource{
+			{4, 0}: {Input: 1, Index: 0},
+			{1, 3}: {Input: 1, Index: 1},
+			{4, 5}: {Input: 1, Index: 2},
+		}
+		ring := result.Geom[0][0]
+		vertices := result.Vertices[0][0]
+		edges := result.Edges[0][0]
+		found := 0
+		for k := 0; k < len(ring)-1; k++ {
+			if source, ok := sources[[2]float64{ring[k][0], ring[k][1]}]; ok {
+				found++
+				expect(t, len(vertices[k].Sources) == 1 && vertices[k].Sources[0] == source)
+			}
+			expect(t, len(edges[k].Inputs) > 0)
+		}
+		expect(t, found == 3)
+	})
+}
@@ -0,0 +1,155 @@
+package polygol
+
+import (
+	"math"
+	"math/big"
+)
+
+var (
+	// error bound for the floating point filter in orient2d, from
+	// Shewchuk's "Adaptive Precision Floating-Point Arithmetic and Fast
+	// Robust Geometric Predicates"
+	ccwErrBoundA = (3.0 + 16.0*machineEpsilon) * machineEpsilon
+)
+
+const (
+	machineEpsilon = 1.0 / (1 << 53)
+)
+
+// orient2d returns a positive value if a, b and c are in counter-clockwise
+// order, a negative value if they're clockwise and zero if they're colinear.
+// The sign is always exact: the determinant is evaluated in floating point
+// and only recomputed exactly when it's too close to zero to trust.
+func orient2d(a, b, c vector) float64 {
+	detLeft := (a.x - c.x) * (b.y - c.y)
+	detRight := (a.y - c.y) * (b.x - c.x)
+	det := detLeft - detRight
+
+	var detSum float64
+	if detLeft > 0 {
+		if detRight <= 0 {
+			return det
+		}
+		detSum = detLeft + detRight
+	} else if detLeft < 0 {
+		if detRight >= 0 {
+			return det
+		}
+		detSum = -detLeft - detRight
+	} else {
+		return det
+	}
+
+	errBound := ccwErrBoundA * detSum
+	if det >= errBound || -det >= errBound {
+		return det
+	}
+	return orient2dExact(a, b, c)
+}
+
+// orient2dExact evaluates the orientation determinant in exact rational
+// arithmetic, returning only its sign. Non-finite coordinates have no exact
+// value, so for those the floating point determinant is returned as is.
+func orient2dExact(a, b, c vector) float64 {
+	if !isFiniteVectors(a, b, c) {
+		return (a.x-c.x)*(b.y-c.y) - (a.y-c.y)*(b.x-c.x)
+	}
+	rat := func(f float64) *big.Rat {
+		return new(big.Rat).SetFloat64(f)
+	}
+	acx := new(big.Rat).Sub(rat(a.x), rat(c.x))
+	bcy := new(big.Rat).Sub(rat(b.y), rat(c.y))
+	acy := new(big.Rat).Sub(rat(a.y), rat(c.y))
+	bcx := new(big.Rat).Sub(rat(b.x), rat(c.x))
+	detLeft := new(big.Rat).Mul(acx, bcy)
+	detRight := new(big.Rat).Mul(acy, bcx)
+	return float64(detLeft.Cmp(detRight))
+}
+
+// compareAnglesRobust is compareAngles with an exact orientation test in
+// place of the epsilon comparison of the cross product.
+func compareAnglesRobust(basePt, endPt1, endPt2 vector) int {
+	det := orient2d(basePt, endPt1, endPt2)
+	if det > 0 {
+		return 1
+	}
+	if det < 0 {
+		return -1
+	}
+	return 0
+}
+
+// comparePointRobust is comparePoint with an exact orientation test:
+// 1 if the point is above (left of) the segment, -1 if below, 0 if on it.
+func (s *segment) comparePointRobust(point *point) int {
+	if s.isAnEndpoint(point) {
+		return 0
+	}
+	det := orient2d(s.leftSE.point.xy(), s.rightSE.point.xy(), point.xy())
+	if det > 0 {
+		return 1
+	}
+	if det < 0 {
+		return -1
+	}
+	return 0
+}
+
+// robustSine corrects the sign of a sine computed by sineOfAngle using an
+// exact orientation test, so that the above/below x-axis decisions made
+// with it are exact.
+func robustSine(sine float64, pShared, pBase, pAngle vector) float64 {
+	det := orient2d(pShared, pAngle, pBase)
+	if det == 0 {
+		return 0
+	}
+	if sine == 0 {
+		return math.Copysign(math.SmallestNonzeroFloat64, det)
+	}
+	return math.Copysign(sine, det)
+}
+
+// intersectionExact is intersection evaluated in exact rational arithmetic,
+// with the result rounded once to the nearest float. Segment vectors that
+// overflowed have no exact value, so those are left to intersection.
+func intersectionExact(v1, v2 vector, pt1, pt2 vector) (vector, bool) {
+	if !isFiniteVectors(v1, v2, pt1, pt2) {
+		return intersection(v1, v2, pt1, pt2)
+	}
+	rat := func(f float64) *big.Rat {
+		return new(big.Rat).SetFloat64(f)
+	}
+	cross := func(ax, ay, bx, by *big.Rat) *big.Rat {
+		l := new(big.Rat).Mul(ax, by)
+		return l.Sub(l, new(big.Rat).Mul(ay, bx))
+	}
+	v1x, v1y := rat(v1.x), rat(v1.y)
+	v2x, v2y := rat(v2.x), rat(v2.y)
+	kross := cross(v1x, v1y, v2x, v2y)
+	if kross.Sign() == 0 {
+		return vector{}, false
+	}
+	vex := new(big.Rat).Sub(rat(pt2.x), rat(pt1.x))
+	vey := new(big.Rat).Sub(rat(pt2.y), rat(pt1.y))
+	d := cross(vex, vey, v2x, v2y)
+	d.Quo(d, kross)
+	x := new(big.Rat).Mul(d, v1x)
+	x.Add(x, rat(pt1.x))
+	y := new(big.Rat).Mul(d, v1y)
+	y.Add(y, rat(pt1.y))
+	fx, _ := x.Float64()
+	fy, _ := y.Float64()
+	return vector{fx, fy}, true
+}
+
+// isFiniteVectors reports whether none of the coordinates of vs are NaN or
+// infinite, which big.Rat can't represent.
+func isFiniteVectors(vs ...vector) bool {
+	for i := 0; i < len(vs); i++ {
+		if math.IsInf(vs[i].x, 0) || math.IsNaN(vs[i].x) ||
+			math.IsInf(vs[i].y, 0) || math.IsNaN(vs[i].y) {
+			return false
+		}
+	}
+	return true
+}
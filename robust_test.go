@@ -0,0 +1,96 @@
+package polygol
+
+import (
+	"math"
+	"math/rand"
+	"path"
+	"testing"
+)
+
+func TestRobustOrient2d(t *testing.T) {
+	t.Parallel()
+
+	// counter-clockwise, clockwise, colinear
+	expect(t, orient2d(vector{0, 0}, vector{1, 0}, vector{0, 1}) > 0)
+	expect(t, orient2d(vector{0, 0}, vector{0, 1}, vector{1, 0}) < 0)
+	expect(t, orient2d(vector{0.5, 0.5}, vector{12, 12}, vector{24, 24}) == 0)
+
+	// nearly colinear points, where the floating point determinant
+	// can't be trusted, agree with exact arithmetic
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a := vector{0.5, 0.5}
+		b := vector{12, 12}
+		x := 0.5 + r.Float64()*24
+		c := vector{x, math.Nextafter(x, math.Inf(int(r.Int63()%2)*2-1))}
+		exact := int(orient2dExact(a, b, c))
+		expect(t, compareAnglesRobust(a, b, c) == exact)
+	}
+}
+
+func TestRobustComparePoint(t *testing.T) {
+	t.Parallel()
+
+	op := newOperation("")
+	op.opts.robust = true
+
+	seg, err := op.newSegmentFromRing(newPoint(0, 0), newPoint(3, 1), nil)
+	terr(t, err)
+
+	expect(t, seg.comparePoint(newPoint(0, 0)) == 0)
+	expect(t, seg.comparePoint(newPoint(1.5, 0.5)) == 0)
+	expect(t, seg.comparePoint(newPoint(1.5, 1)) == 1)
+	expect(t, seg.comparePoint(newPoint(1.5, 0)) == -1)
+
+	// one ulp off the line
+	y := 1.0 / 3.0
+	expect(t, seg.comparePoint(newPoint(1, math.Nextafter(y, 1))) == 1)
+	expect(t, seg.comparePoint(newPoint(1, math.Nextafter(y, 0))) == -1)
+
+	// vertical segments: left of the upward segment is above
+	seg, err = op.newSegmentFromRing(newPoint(0, 0), newPoint(0, 1), nil)
+	terr(t, err)
+	expect(t, seg.comparePoint(newPoint(-1, 0.5)) == 1)
+	expect(t, seg.comparePoint(newPoint(1, 0.5)) == -1)
+}
+
+func TestRobustOperation(t *testing.T) {
+	t.Parallel()
+
+	dir := path.Join(endToEndDir, "very-small-polygon")
+	args, err := loadGeoms(path.Join(dir, "args.geojson"))
+	terr(t, err)
+	expected, err := loadGeoms(path.Join(dir, "union.geojson"))
+	terr(t, err)
+
+	result, err := New(WithRobustPredicates()).Union(args[0], args[1:]...)
+	terr(t, err)
+	equal, err := EqualTopo(expected[0], result, 1e-9)
+	terr(t, err)
+	expect(t, equal)
+}
+
+func TestRobustNonFinite(t *testing.T) {
+	t.Parallel()
+
+	inf := math.Inf(1)
+	a := vector{0, 0}
+	b := vector{inf, 1}
+	c := vector{1, math.NaN()}
+	orient2dExact(a, b, c)
+	intersectionExact(b, vector{1, 1}, a, c)
+
+	square := Geom{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}
+	for _, bad := range []float64{math.NaN(), inf, -inf} {
+		geom := Geom{{{{0, 0}, {bad, 0}, {1, 1}, {0, 0}}}}
+		for _, p := range []*Polygol{
+			New(),
+			New(WithRobustPredicates()),
+			New(WithPrecision(0.5)),
+			New(WithFallback()),
+		} {
+			_, err := p.Union(square, geom)
+			expect(t, err != nil)
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package polygol
 
 import (
-	splaytree "github.com/engelsjk/splay-tree"
+	"math"
 )
 
 type ptRounder struct {
-	xRounder *coordRounder
-	yRounder *coordRounder
+	xRounder     *coordRounder
+	yRounder     *coordRounder
+	tolerance    Tolerance
+	snapDistance float64
+	gridRounder  *gridRounder
+	arena        *arena
+	base         *ptRounder // read-only, see fork
 }
 
 func newPtRounder() *ptRounder {
@@ -16,61 +21,180 @@ func newPtRounder() *ptRounder {
 }
 
 func (pr *ptRounder) reset() {
-	pr.xRounder = newCoordRounder()
-	pr.yRounder = newCoordRounder()
+	var nodes *slab[splayNode[float64]]
+	if pr.arena != nil {
+		nodes = &pr.arena.coordNodes
+	}
+	pr.xRounder = newCoordRounder(nodes)
+	pr.xRounder.tolerance = pr.tolerance
+	pr.yRounder = newCoordRounder(nodes)
+	pr.yRounder.tolerance = pr.tolerance
+	pr.gridRounder = nil
+	if pr.snapDistance > 0 {
+		pr.gridRounder = newGridRounder(pr.snapDistance)
+	}
+	if pr.base != nil {
+		pr.xRounder.base = pr.base.xRounder
+		pr.yRounder.base = pr.base.yRounder
+		if pr.gridRounder != nil {
+			pr.gridRounder.base = pr.base.gridRounder
+		}
+	}
+}
+
+// fork returns a rounder that rounds points as pr would, but keeps those it
+// rounds to itself, so that forks of the same rounder can round points
+// concurrently. pr mustn't round any more points while its forks are in use.
+func (pr *ptRounder) fork(arena *arena) *ptRounder {
+	f := &ptRounder{
+		tolerance:    pr.tolerance,
+		snapDistance: pr.snapDistance,
+		arena:        arena,
+		base:         pr,
+	}
+	f.reset()
+	return f
 }
 
 func (pr *ptRounder) round(x, y float64) *point {
-	return newPoint(
+	if pr.gridRounder != nil {
+		if nearest := pr.gridRounder.nearest(x, y); nearest != nil {
+			return pr.newPoint(nearest[0], nearest[1])
+		}
+	}
+	pt := pr.newPoint(
 		pr.xRounder.round(x),
 		pr.yRounder.round(y),
 	)
+	if pr.gridRounder != nil {
+		pr.gridRounder.add(pt.x, pt.y)
+	}
+	return pt
+}
+
+func (pr *ptRounder) newPoint(x, y float64) *point {
+	if pr.arena != nil {
+		return pr.arena.newPoint(x, y)
+	}
+	return newPoint(x, y)
 }
 
 type coordRounder struct {
-	tree *splaytree.SplayTree
+	tree      *splayTree[float64]
+	tolerance Tolerance
+	base      *coordRounder // coordinates seen by the rounder forked from
 }
 
-func newCoordRounder() *coordRounder {
+func newCoordRounder(nodes *slab[splayNode[float64]]) *coordRounder {
 	cr := new(coordRounder)
-	less := func(a, b interface{}) int {
-		af := a.(float64)
-		bf := b.(float64)
-		if af > bf {
+	less := func(a, b float64) int {
+		if a > b {
 			return 1
 		}
-		if af < bf {
+		if a < b {
 			return -1
 		}
 		return 0
 	}
-	cr.tree = splaytree.New(less)
+	cr.tree = newSplayTree(less)
+	cr.tree.nodes = nodes
 	cr.round(0.0)
 	return cr
 }
 
 func (cr *coordRounder) round(coord float64) float64 {
 
-	node := cr.tree.Add(coord)
-	item := node.Item().(float64)
+	var basePrev, baseNext *splayNode[float64]
+	if cr.base != nil {
+		var baseNode *splayNode[float64]
+		basePrev, baseNode, baseNext = cr.base.tree.around(coord)
+		if baseNode != nil {
+			return coord
+		}
+	}
+
+	node := cr.tree.add(coord)
+	item := node.item
 
-	prevNode := cr.tree.Prev(node)
+	prevNode := cr.tree.prev(node)
+	if basePrev != nil && (prevNode == nil || basePrev.item > prevNode.item) {
+		prevNode = basePrev
+	}
 	if prevNode != nil {
-		prevItem := prevNode.Item().(float64)
-		if flpCmp(item, prevItem) == 0 {
-			cr.tree.Remove(coord)
+		prevItem := prevNode.item
+		if cr.tolerance.cmp(item, prevItem) == 0 {
+			cr.tree.remove(coord)
 			return prevItem
 		}
 	}
 
-	nextNode := cr.tree.Next(node)
+	nextNode := cr.tree.next(node)
+	if baseNext != nil && (nextNode == nil || baseNext.item < nextNode.item) {
+		nextNode = baseNext
+	}
 	if nextNode != nil {
-		nextItem := nextNode.Item().(float64)
-		if flpCmp(item, nextItem) == 0 {
-			cr.tree.Remove(coord)
+		nextItem := nextNode.item
+		if cr.tolerance.cmp(item, nextItem) == 0 {
+			cr.tree.remove(coord)
 			return nextItem
 		}
 	}
 
 	return coord
 }
+
+// gridRounder snaps points to the nearest point seen before within a
+// distance, looking them up in a hash of grid cells as wide as the distance.
+type gridRounder struct {
+	distance float64
+	cells    map[[2]int64][][2]float64
+	base     *gridRounder // points seen by the rounder forked from
+}
+
+func newGridRounder(distance float64) *gridRounder {
+	return &gridRounder{
+		distance: distance,
+		cells:    map[[2]int64][][2]float64{},
+	}
+}
+
+func (gr *gridRounder) cell(x, y float64) [2]int64 {
+	return [2]int64{
+		int64(math.Floor(x / gr.distance)),
+		int64(math.Floor(y / gr.distance)),
+	}
+}
+
+// nearest finds the closest point seen within the distance, if any.
+func (gr *gridRounder) nearest(x, y float64) []float64 {
+	c := gr.cell(x, y)
+
+	// points within the distance can only be in this or a neighboring cell
+	var nearest []float64
+	nearestDistSq := gr.distance * gr.distance
+	visit := func(pts [][2]float64) {
+		for k := 0; k < len(pts); k++ {
+			dx, dy := pts[k][0]-x, pts[k][1]-y
+			distSq := dx*dx + dy*dy
+			if distSq <= nearestDistSq {
+				nearest = pts[k][:]
+				nearestDistSq = distSq
+			}
+		}
+	}
+	for i := c[0] - 1; i <= c[0]+1; i++ {
+		for j := c[1] - 1; j <= c[1]+1; j++ {
+			// points of the base come first, as if they had been added here
+			if gr.base != nil {
+				visit(gr.base.cells[[2]int64{i, j}])
+			}
+			visit(gr.cells[[2]int64{i, j}])
+		}
+	}
+	return nearest
+}
+
+func (gr *gridRounder) add(x, y float64) {
+	c := gr.cell(x, y)
+	gr.cells[c] = append(gr.cells[c], [2]float64{x, y})
+}
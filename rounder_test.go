@@ -1,6 +1,7 @@
 package polygol
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -62,3 +63,98 @@ func TestRounderRound(t *testing.T) {
 		expect(t, rounder.round(pt1.x, pt1.y).equal(point{x: 0, y: 0}))
 	})
 }
+
+func TestRounderSnapDistance(t *testing.T) {
+	t.Parallel()
+
+	newRounder := func(distance float64) *ptRounder {
+		rounder := newPtRounder()
+		rounder.snapDistance = distance
+		rounder.reset()
+		return rounder
+	}
+
+	t.Run("merges-in-any-direction", func(t *testing.T) {
+		t.Parallel()
+		rounder := newRounder(0.1)
+		expect(t, rounder.round(1, 1).equal(point{x: 1, y: 1}))
+		expect(t, rounder.round(1.05, 0.95).equal(point{x: 1, y: 1}))
+		expect(t, rounder.round(0.95, 1.05).equal(point{x: 1, y: 1}))
+	})
+
+	t.Run("keeps-points-apart-beyond-the-distance", func(t *testing.T) {
+		t.Parallel()
+		rounder := newRounder(0.1)
+		expect(t, rounder.round(1, 1).equal(point{x: 1, y: 1}))
+		expect(t, rounder.round(1.08, 1.08).equal(point{x: 1.08, y: 1.08}))
+	})
+
+	t.Run("picks-the-nearest", func(t *testing.T) {
+		t.Parallel()
+		rounder := newRounder(0.1)
+		rounder.round(1, 1)
+		rounder.round(1.15, 1)
+		expect(t, rounder.round(1.09, 1).equal(point{x: 1.15, y: 1}))
+	})
+
+	t.Run("across-cells", func(t *testing.T) {
+		t.Parallel()
+		rounder := newRounder(1)
+		rounder.round(0.99, -0.01)
+		expect(t, rounder.round(1.01, 0.01).equal(point{x: 0.99, y: -0.01}))
+	})
+
+	t.Run("off-without-a-distance", func(t *testing.T) {
+		t.Parallel()
+		rounder := newRounder(0)
+		rounder.round(1, 1)
+		expect(t, rounder.round(1.05, 0.95).equal(point{x: 1.05, y: 0.95}))
+	})
+}
+
+func TestRounderFork(t *testing.T) {
+	t.Parallel()
+
+	base := newPtRounder()
+	base.snapDistance = 0.1
+	base.reset()
+	base.round(3, 4)
+
+	fork := base.fork(nil)
+	fork2 := base.fork(nil)
+	// coordinates the base has seen are rounded to
+	expect(t, fork.round(3+epsilon, 5).equal(point{x: 3, y: 5}))
+	expect(t, fork.round(1.05, 3.95).equal(point{x: 1.05, y: 3.95}))
+	expect(t, fork.round(3.05, 3.95).equal(point{x: 3, y: 4}))
+	// but those a fork has seen stay its own
+	expect(t, fork.round(1.05+epsilon, 6).equal(point{x: 1.05, y: 6}))
+	expect(t, fork2.round(1.05+epsilon, 6).equal(point{x: 1.05 + epsilon, y: 6}))
+	expect(t, base.round(1.05+epsilon, 6).equal(point{x: 1.05 + epsilon, y: 6}))
+}
+
+func TestWithSnapDistance(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}
+	b := Geom{{{{1.0003, 0.0003}, {2, 0.0003}, {2, 1.0003}, {1.0003, 1.0003}, {1.0003, 0.0003}}}}
+
+	union, err := Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 2)
+
+	union, err = New(WithSnapDistance(1e-3)).Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 1)
+	expect(t, len(union[0]) == 1)
+
+	// the tip of c is within the distance of a corner of both a and d, and
+	// goes to d's, which is nearer, though a's is seen first
+	c := Geom{{{{1.09, -0.01}, {1.5, -1}, {1.09, -1}, {1.09, -0.01}}}}
+	d := Geom{{{{1.15, 0}, {2, 0}, {2, 1}, {1.15, 0}}}}
+	union, err = New(WithSnapDistance(0.1), WithNormalize()).Union(a, d, c)
+	terr(t, err)
+	snapped := Geom{{{{1.15, 0}, {1.5, -1}, {1.09, -1}, {1.15, 0}}}}
+	want, err := New(WithNormalize()).Union(a, d, snapped)
+	terr(t, err)
+	expect(t, reflect.DeepEqual(union, want))
+}
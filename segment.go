@@ -23,9 +23,9 @@ type segment struct {
 }
 
 func (o *operation) newSegment(leftSE, rightSE *sweepEvent, rings []*ringIn, windings []int) *segment {
-	o.segmentID++
+	o.segmentID += o.segmentIDStep
 
-	s := &segment{}
+	s := o.alloc().segments.alloc()
 	s.id = o.segmentID
 	s.leftSE = leftSE
 
@@ -45,10 +45,7 @@ func (o *operation) newSegment(leftSE, rightSE *sweepEvent, rings []*ringIn, win
 	return s
 }
 
-func segmentCompare(a, b interface{}) int {
-
-	aSeg := a.(*segment)
-	bSeg := b.(*segment)
+func segmentCompare(aSeg, bSeg *segment) int {
 
 	alx := aSeg.leftSE.point.x
 	blx := bSeg.leftSE.point.x
@@ -214,7 +211,7 @@ func (o *operation) newSegmentFromRing(pt1, pt2 *point, ring *ringIn) (*segment,
 	var leftPt, rightPt *point
 	var winding int
 
-	cmpPts := sweepEventComparePoints(pt1, pt2)
+	cmpPts := o.comparePoints(pt1, pt2)
 	if cmpPts < 0 {
 		leftPt = pt1
 		rightPt = pt2
@@ -227,10 +224,15 @@ func (o *operation) newSegmentFromRing(pt1, pt2 *point, ring *ringIn) (*segment,
 		return nil, fmt.Errorf("Tried to create degenerate segment at [%f,%f].", pt1.x, pt1.y)
 	}
 
-	leftSE := newSweepEvent(leftPt, true)
-	rightSE := newSweepEvent(rightPt, false)
+	leftSE := o.newSweepEvent(leftPt, true)
+	rightSE := o.newSweepEvent(rightPt, false)
 
-	return o.newSegment(leftSE, rightSE, []*ringIn{ring}, []int{winding}), nil
+	a := o.alloc()
+	rings := a.rings.make(1, 1)
+	rings[0] = ring
+	windings := a.windings.make(1, 1)
+	windings[0] = winding
+	return o.newSegment(leftSE, rightSE, rings, windings), nil
 }
 
 func (s *segment) replaceRightSE(newRightSE *sweepEvent) {
@@ -261,11 +263,8 @@ func (s *segment) bbox() bbox {
 	}
 }
 
-func (s *segment) vector() []float64 {
-	return []float64{
-		s.rightSE.point.x - s.leftSE.point.x,
-		s.rightSE.point.y - s.leftSE.point.y,
-	}
+func (s *segment) vector() vector {
+	return s.rightSE.point.xy().sub(s.leftSE.point.xy())
 }
 
 func (s *segment) isAnEndpoint(point *point) bool {
@@ -294,6 +293,10 @@ func (s *segment) isAnEndpoint(point *point) bool {
 
 func (s *segment) comparePoint(point *point) int {
 
+	if s.op != nil && s.op.opts.robust {
+		return s.comparePointRobust(point)
+	}
+
 	if s.isAnEndpoint(point) {
 		return 0
 	}
@@ -302,10 +305,15 @@ func (s *segment) comparePoint(point *point) int {
 	rPt := s.rightSE.point
 	v := s.vector()
 
+	tol := Tolerance{}
+	if s.op != nil {
+		tol = s.op.opts.tolerance
+	}
+
 	// Exactly vertical segments.
 
-	if almostEqual(lPt.x, rPt.x) {
-		return flpCmp(point.x, lPt.x)
+	if tol.equal(lPt.x, rPt.x) {
+		return tol.cmp(point.x, lPt.x)
 	}
 
 	// original implementation
@@ -321,10 +329,10 @@ func (s *segment) comparePoint(point *point) int {
 
 	// Nearly vertical segments with an intersection.
 	// Check to see where a point on the line with matching Y coordinate is.
-	yDist := (point.y - lPt.y) / v[1]
-	xFromYDist := lPt.x + yDist*v[0]
+	yDist := (point.y - lPt.y) / v.y
+	xFromYDist := lPt.x + yDist*v.x
 
-	if almostEqual(point.x, xFromYDist) {
+	if tol.equal(point.x, xFromYDist) {
 		return 0
 	}
 
@@ -335,10 +343,10 @@ func (s *segment) comparePoint(point *point) int {
 
 	// General case.
 	// Check to see where a point on the line with matching X coordinate is.
-	xDist := (point.x - lPt.x) / v[0]
-	yFromXDist := lPt.y + xDist*v[1]
+	xDist := (point.x - lPt.x) / v.x
+	yFromXDist := lPt.y + xDist*v.y
 
-	return flpCmp(point.y, yFromXDist)
+	return tol.cmp(point.y, yFromXDist)
 
 	// original implementation
 	// if point.y == yFromXDist {
@@ -360,8 +368,8 @@ func (s *segment) getIntersection(other *segment) *point {
 	segBbox := s.bbox()
 	otherBbox := other.bbox()
 
-	bboxOverlap := segBbox.getBboxOverlap(otherBbox)
-	if bboxOverlap == nil {
+	bboxOverlap, ok := segBbox.overlap(otherBbox)
+	if !ok {
 		return nil
 	}
 
@@ -437,35 +445,33 @@ func (s *segment) getIntersection(other *segment) *point {
 	// None of our endpoints intersect. Look for a general intersection between
 	// infinite lines laid over the segments
 
-	pt := intersection(
+	intersect := intersection
+	if s.op.opts.exact {
+		intersect = intersectionExact
+	}
+	pt, ok := intersect(
 		s.vector(),
 		other.vector(),
 		tlp.xy(),
 		olp.xy(),
 	)
-	var ptInter *point
-	if pt != nil {
-		ptInter = newPoint(pt[0], pt[1])
-	}
 
 	// ptInter := lineToLineIntersection(
 	// 	s.leftSE.point, s.rightSE.point,
 	// 	other.leftSE.point, other.rightSE.point)
 
-	defer func() { ptInter = nil }() // clean up dangling pointer
-
 	// are the segments parallel? Note that if they were colinear with overlap,
 	// they would have an endpoint intersection and that case was already handled above
-	if ptInter == nil {
+	if !ok {
 		return nil
 	}
 
 	// is the intersection found between the lines not on the segments?
-	if !bboxOverlap.isInBbox(*ptInter) {
+	if !bboxOverlap.isInBbox(point{x: pt.x, y: pt.y}) {
 		return nil
 	}
 
-	return s.op.rounder.round(ptInter.x, ptInter.y)
+	return s.op.round(pt.x, pt.y)
 }
 
 func lineToLineIntersection(
@@ -516,18 +522,22 @@ func (s *segment) split(point *point) []*sweepEvent {
 	newEvents := []*sweepEvent{}
 	alreadyLinked := point.events != nil
 
-	newLeftSE := newSweepEvent(point, true)
-	newRightSE := newSweepEvent(point, false)
+	// carry Z/M values over to points computed along the segment
+	point.interpolateZM(s.leftSE.point, s.rightSE.point)
+
+	newLeftSE := s.op.newSweepEvent(point, true)
+	newRightSE := s.op.newSweepEvent(point, false)
 	oldRightSE := s.rightSE
 
 	s.replaceRightSE(newRightSE)
 	newEvents = append(newEvents, newRightSE)
 	newEvents = append(newEvents, newLeftSE)
 
-	newRings := make([]*ringIn, len(s.rings))
+	a := s.op.alloc()
+	newRings := a.rings.make(len(s.rings), len(s.rings))
 	copy(newRings, s.rings)
 
-	newWindings := make([]int, len(s.windings))
+	newWindings := a.windings.make(len(s.windings), len(s.windings))
 	copy(newWindings, s.windings)
 
 	newSeg := s.op.newSegment(newLeftSE, oldRightSE, newRings, newWindings)
@@ -535,10 +545,10 @@ func (s *segment) split(point *point) []*sweepEvent {
 	// when splitting a nearly vertical downward-facing segment,
 	// sometimes one of the resulting new segments is vertical, in which
 	// case its left and right events may need to be swapped
-	if sweepEventComparePoints(newSeg.leftSE.point, newSeg.rightSE.point) > 0 {
+	if s.op.comparePoints(newSeg.leftSE.point, newSeg.rightSE.point) > 0 {
 		newSeg.swapEvents()
 	}
-	if sweepEventComparePoints(s.leftSE.point, s.rightSE.point) > 0 {
+	if s.op.comparePoints(s.leftSE.point, s.rightSE.point) > 0 {
 		s.swapEvents()
 	}
 
@@ -629,16 +639,30 @@ type state struct {
 	multiPolys []*multiPolyIn
 }
 
+// isFilled reports whether the area described by the state is covered when
+// loose rings are nested. Containment uses the even-odd rule over rings,
+// orientation uses the non-zero rule over the summed windings.
+func (st *state) isFilled(nesting Nesting) bool {
+	count := 0
+	for i := 0; i < len(st.windings); i++ {
+		if nesting == NestByOrientation {
+			count += st.windings[i]
+		} else if st.windings[i] != 0 {
+			count++
+		}
+	}
+	if nesting == NestByOrientation {
+		return count != 0
+	}
+	return count%2 == 1
+}
+
 func (s *segment) beforeState() *state {
 	if s.before != nil {
 		return s.before
 	}
 	if s.prev == nil {
-		s.before = &state{
-			rings:      []*ringIn{},
-			windings:   []int{},
-			multiPolys: []*multiPolyIn{},
-		}
+		s.before = s.op.alloc().newState(0)
 	} else {
 		seg := s.prev.consumedBy
 		if s.prev.consumedBy == nil {
@@ -656,16 +680,12 @@ func (s *segment) afterState() *state {
 
 	beforeState := s.beforeState()
 
-	ringsBefore := make([]*ringIn, len(beforeState.rings))
-	copy(ringsBefore, beforeState.rings)
-	windingsBefore := make([]int, len(beforeState.windings))
-	copy(windingsBefore, beforeState.windings)
-
-	s.after = &state{
-		rings:      ringsBefore,
-		windings:   windingsBefore,
-		multiPolys: []*multiPolyIn{},
-	}
+	// leave room for our own rings
+	a := s.op.alloc()
+	numRings := len(beforeState.rings) + len(s.rings)
+	s.after = a.newState(numRings)
+	s.after.rings = append(s.after.rings, beforeState.rings...)
+	s.after.windings = append(s.after.windings, beforeState.windings...)
 
 	// calculate ringsAfter, windingsAfter
 	for i := 0; i < len(s.rings); i++ {
@@ -681,8 +701,8 @@ func (s *segment) afterState() *state {
 	}
 
 	// calculate polysAfter
-	polysAfter := []*polyIn{}
-	polysExclude := []*polyIn{}
+	polysAfter := a.polys.make(0, len(s.after.rings))
+	polysExclude := a.polys.make(0, len(s.after.rings))
 	for i := 0; i < len(s.after.rings); i++ {
 		if s.after.windings[i] == 0 { // non-zero rule
 			continue
@@ -770,6 +790,11 @@ func (s *segment) isInResult() bool {
 			return len(mps) == 1 && mps[0].isSubject
 		}
 		s.inResult = isJustSubject(mpsBefore) != isJustSubject(mpsAfter)
+	case "build":
+		// BUILD - included iff:
+		//  * we separate filled from unfilled, where filled is decided
+		//    by the nesting rule of the operation
+		s.inResult = s.beforeState().isFilled(s.op.nesting) != s.afterState().isFilled(s.op.nesting)
 	default:
 		fmt.Printf("Unrecognized operation type found %s", s.op.opType)
 	}
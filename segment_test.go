@@ -204,6 +204,37 @@ func TestSegmentSplit(t *testing.T) {
 	}
 	expect(t, evt.segment == orgRightEvt.segment)
 }
+
+func TestSegmentSplitInterpolatesZM(t *testing.T) {
+	t.Parallel()
+
+	op := newOperation("")
+
+	p1 := &point{x: 0, y: 0, zm: []float64{10, 0}}
+	p2 := &point{x: 4, y: 4, zm: []float64{30, 8}}
+	seg, err := op.newSegmentFromRing(p1, p2, nil)
+	terr(t, err)
+
+	// computed point gets interpolated values
+	pt := &point{x: 1, y: 1}
+	seg.split(pt)
+	expect(t, equalVector(pt.zm, []float64{15, 2}))
+
+	// existing values are kept
+	seg, err = op.newSegmentFromRing(p1, p2, nil)
+	terr(t, err)
+	pt = &point{x: 2, y: 2, zm: []float64{0, 0}}
+	seg.split(pt)
+	expect(t, equalVector(pt.zm, []float64{0, 0}))
+
+	// nothing to interpolate from
+	seg, err = op.newSegmentFromRing(newPoint(0, 0), newPoint(4, 4), nil)
+	terr(t, err)
+	pt = &point{x: 2, y: 2}
+	seg.split(pt)
+	expect(t, pt.zm == nil)
+}
+
 func TestSegmentSimplePropertiesBboxVector(t *testing.T) {
 	t.Parallel()
 
@@ -217,22 +248,23 @@ func TestSegmentSimplePropertiesBboxVector(t *testing.T) {
 	terr(t, err)
 
 	expect(t, equalBbox(seg.bbox(), bbox{ll: point{x: 1, y: 2}, ur: point{x: 3, y: 4}}))
-	expect(t, equalVector(seg.vector(), []float64{2, 2}))
+	expect(t, seg.vector() == vector{2, 2})
 
 	// horizontal
 	seg, err = op.newSegmentFromRing(&point{x: 1, y: 4}, &point{x: 3, y: 4}, nil)
 	terr(t, err)
 
 	expect(t, equalBbox(seg.bbox(), bbox{ll: point{x: 1, y: 4}, ur: point{x: 3, y: 4}}))
-	expect(t, equalVector(seg.vector(), []float64{2, 0}))
+	expect(t, seg.vector() == vector{2, 0})
 
 	// vertical
 	seg, err = op.newSegmentFromRing(&point{x: 3, y: 2}, &point{x: 3, y: 4}, nil)
 	terr(t, err)
 
 	expect(t, equalBbox(seg.bbox(), bbox{ll: point{x: 3, y: 2}, ur: point{x: 3, y: 4}}))
-	expect(t, equalVector(seg.vector(), []float64{0, 2}))
+	expect(t, seg.vector() == vector{0, 2})
 }
+
 func TestSegmentConsume(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,238 @@
+package polygol
+
+import (
+	"math"
+	"sort"
+)
+
+// subjectSnapper snaps clip geometries onto the vertices and edges of the
+// subject, so that boundaries that are meant to be shared become exactly
+// coincident.
+type subjectSnapper struct {
+	tolerance float64
+	// vertices of the subject, sorted by x
+	vertices [][]float64
+	// edges of the subject, in ring order, with their bboxes
+	edges      [][2][]float64
+	edgeBboxes []bbox
+}
+
+func newSubjectSnapper(subject Geom, tolerance float64) *subjectSnapper {
+	ss := &subjectSnapper{
+		tolerance:  tolerance,
+		vertices:   [][]float64{},
+		edges:      [][2][]float64{},
+		edgeBboxes: []bbox{},
+	}
+	for i := 0; i < len(subject); i++ {
+		for j := 0; j < len(subject[i]); j++ {
+			ring := subject[i][j]
+			for k := 0; k < len(ring); k++ {
+				position := ring[k]
+				if len(position) < 2 {
+					continue
+				}
+				ss.vertices = append(ss.vertices, position[:2])
+				if k+1 == len(ring) || len(ring[k+1]) < 2 {
+					continue
+				}
+				next := ring[k+1]
+				ss.edges = append(ss.edges, [2][]float64{position, next})
+				ss.edgeBboxes = append(ss.edgeBboxes, bbox{
+					ll: point{x: math.Min(position[0], next[0]), y: math.Min(position[1], next[1])},
+					ur: point{x: math.Max(position[0], next[0]), y: math.Max(position[1], next[1])},
+				})
+			}
+		}
+	}
+	sort.SliceStable(ss.vertices, func(i, j int) bool {
+		return comparePositions(ss.vertices[i], ss.vertices[j]) < 0
+	})
+	return ss
+}
+
+// snapGeom copies geom with each of its vertices snapped onto the subject,
+// and subject vertices near its edges inserted into them.
+func (ss *subjectSnapper) snapGeom(geom Geom) Geom {
+	near := ss.nearEdges(geom)
+	out := make(Geom, 0, len(geom))
+	n := 0
+	for i := 0; i < len(geom); i++ {
+		poly := make([][][]float64, 0, len(geom[i]))
+		for j := 0; j < len(geom[i]); j++ {
+			ring := geom[i][j]
+			poly = append(poly, ss.snapRing(ring, near[n:n+len(ring)]))
+			n += len(ring)
+		}
+		out = append(out, poly)
+	}
+	return out
+}
+
+// nearEdges finds the subject edges whose bboxes are within the tolerance
+// of each vertex of geom, sweeping over both at once. They're listed by
+// vertex, in ring order.
+func (ss *subjectSnapper) nearEdges(geom Geom) [][]int {
+	bboxes := append([]bbox{}, ss.edgeBboxes...)
+	for i := 0; i < len(geom); i++ {
+		for j := 0; j < len(geom[i]); j++ {
+			for k := 0; k < len(geom[i][j]); k++ {
+				position := geom[i][j][k]
+				if len(position) < 2 {
+					// empty, so never overlapping
+					bboxes = append(bboxes, bbox{ll: point{x: 1}, ur: point{x: 0}})
+					continue
+				}
+				pt := point{x: position[0], y: position[1]}
+				bboxes = append(bboxes, bbox{ll: pt, ur: pt})
+			}
+		}
+	}
+
+	numEdges := len(ss.edges)
+	near := make([][]int, len(bboxes)-numEdges)
+	forEachOverlap(bboxes, ss.tolerance/2, func(i, j int) {
+		// edges come first, so only i can be one
+		if i < numEdges && j >= numEdges {
+			near[j-numEdges] = append(near[j-numEdges], i)
+		}
+	})
+	for i := 0; i < len(near); i++ {
+		sort.Ints(near[i])
+	}
+	return near
+}
+
+// snapRing snaps a ring, given the subject edges near each of its vertices.
+func (ss *subjectSnapper) snapRing(ring [][]float64, near [][]int) [][]float64 {
+	snapped := make([][]float64, 0, len(ring))
+	for i := 0; i < len(ring); i++ {
+		snapped = append(snapped, ss.snapVertex(ring[i], near[i]))
+	}
+	out := make([][]float64, 0, len(snapped))
+	for i := 0; i < len(snapped); i++ {
+		out = append(out, snapped[i])
+		if i+1 < len(snapped) {
+			out = append(out, ss.verticesAlong(snapped[i], snapped[i+1])...)
+		}
+	}
+	// rings may be left open, in which case the closing edge is implied
+	if n := len(snapped); n > 1 && comparePositions(snapped[0][:2], snapped[n-1][:2]) != 0 {
+		out = append(out, ss.verticesAlong(snapped[n-1], snapped[0])...)
+	}
+	return out
+}
+
+// snapVertex moves a position onto the nearest subject vertex within the
+// tolerance, or failing that the nearest point on one of the given subject
+// edges. Any Z/M values are kept.
+func (ss *subjectSnapper) snapVertex(position []float64, edges []int) []float64 {
+	out := append([]float64{}, position...)
+	if len(position) < 2 {
+		return out
+	}
+	x, y := position[0], position[1]
+
+	var nearest []float64
+	nearestDist := ss.tolerance
+	i := sort.Search(len(ss.vertices), func(i int) bool {
+		return ss.vertices[i][0] >= x-ss.tolerance
+	})
+	for ; i < len(ss.vertices) && ss.vertices[i][0] <= x+ss.tolerance; i++ {
+		v := ss.vertices[i]
+		dist := math.Hypot(v[0]-x, v[1]-y)
+		if dist <= nearestDist {
+			nearest = v
+			nearestDist = dist
+		}
+	}
+
+	if nearest == nil {
+		for i := 0; i < len(edges); i++ {
+			edge := ss.edges[edges[i]]
+			pt := closestPointOnSegment(position, edge[0], edge[1])
+			dist := math.Hypot(pt[0]-x, pt[1]-y)
+			if dist <= nearestDist {
+				nearest = pt
+				nearestDist = dist
+			}
+		}
+	}
+
+	if nearest != nil {
+		out[0], out[1] = nearest[0], nearest[1]
+	}
+	return out
+}
+
+// verticesAlong finds the subject vertices within the tolerance of the edge
+// from one position to another, other than at its ends. It returns them
+// ordered from start to end, with any Z/M values interpolated.
+func (ss *subjectSnapper) verticesAlong(from, to []float64) [][]float64 {
+	minX := math.Min(from[0], to[0]) - ss.tolerance
+	maxX := math.Max(from[0], to[0]) + ss.tolerance
+
+	fromV, toV := positionVector(from), positionVector(to)
+	v := toV.sub(fromV)
+	lenSq := dotProduct(v, v)
+	if lenSq == 0 {
+		return nil
+	}
+
+	type hit struct {
+		t        float64
+		position []float64
+	}
+	hits := []hit{}
+
+	i := sort.Search(len(ss.vertices), func(i int) bool {
+		return ss.vertices[i][0] >= minX
+	})
+	for ; i < len(ss.vertices) && ss.vertices[i][0] <= maxX; i++ {
+		c := ss.vertices[i]
+		if (c[0] == from[0] && c[1] == from[1]) || (c[0] == to[0] && c[1] == to[1]) {
+			continue
+		}
+		t := dotProduct(positionVector(c).sub(fromV), v) / lenSq
+		if t <= 0 || t >= 1 {
+			continue
+		}
+		if distanceToSegment(positionVector(c), fromV, toV) > ss.tolerance {
+			continue
+		}
+		// a subject vertex is listed again where its ring closes
+		if len(hits) > 0 && comparePositions(hits[len(hits)-1].position[:2], c) == 0 {
+			continue
+		}
+		position := []float64{c[0], c[1]}
+		for k := 2; k < len(from) && k < len(to); k++ {
+			position = append(position, from[k]+t*(to[k]-from[k]))
+		}
+		hits = append(hits, hit{t: t, position: position})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].t < hits[j].t
+	})
+	positions := make([][]float64, len(hits))
+	for i := 0; i < len(hits); i++ {
+		positions[i] = hits[i].position
+	}
+	return positions
+}
+
+// closestPointOnSegment is the point of the segment nearest to pt.
+func closestPointOnSegment(pt, segStart, segEnd []float64) []float64 {
+	v := positionVector(segEnd).sub(positionVector(segStart))
+	lenSq := dotProduct(v, v)
+	if lenSq == 0 {
+		return []float64{segStart[0], segStart[1]}
+	}
+	t := dotProduct(positionVector(pt).sub(positionVector(segStart)), v) / lenSq
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return []float64{segStart[0] + t*v.x, segStart[1] + t*v.y}
+}
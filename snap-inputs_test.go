@@ -0,0 +1,97 @@
+package polygol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubjectSnapperSnapVertex(t *testing.T) {
+	t.Parallel()
+
+	subject := Geom{{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}}
+	ss := newSubjectSnapper(subject, 0.1)
+	snapVertex := func(position []float64) []float64 {
+		return ss.snapVertex(position, ss.nearEdges(Geom{{{position}}})[0])
+	}
+
+	// onto a vertex, keeping Z
+	expect(t, equalVector(snapVertex([]float64{10.05, 0.05, 7}), []float64{10, 0, 7}))
+
+	// onto an edge
+	expect(t, equalVector(snapVertex([]float64{10.05, 5}), []float64{10, 5}))
+
+	// vertices win over edges
+	expect(t, equalVector(snapVertex([]float64{10.02, 9.95}), []float64{10, 10}))
+
+	// too far
+	expect(t, equalVector(snapVertex([]float64{10.5, 5}), []float64{10.5, 5}))
+}
+
+func TestSubjectSnapperNearEdges(t *testing.T) {
+	t.Parallel()
+
+	subject := Geom{
+		{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		{{{20, 0}, {30, 0}, {20, 10}, {20, 0}}},
+	}
+	ss := newSubjectSnapper(subject, 0.1)
+	expect(t, len(ss.edges) == 7)
+
+	near := ss.nearEdges(Geom{{
+		{{10.05, 5}, {25, 5.05}, {15, 5}, {0.05, 10.05}, {}},
+	}})
+	expect(t, len(near) == 5)
+	// the right edge of the square
+	expect(t, reflect.DeepEqual(near[0], []int{1}))
+	// the triangle's slanted edge, but not its upright one
+	expect(t, reflect.DeepEqual(near[1], []int{5}))
+	// between the two
+	expect(t, len(near[2]) == 0)
+	// a corner, next to both its edges
+	expect(t, reflect.DeepEqual(near[3], []int{2, 3}))
+	expect(t, len(near[4]) == 0)
+}
+
+func TestSubjectSnapperVerticesAlong(t *testing.T) {
+	t.Parallel()
+
+	subject := Geom{{{{0, 0}, {10, 0}, {10, 5}, {10, 10}, {0, 10}, {0, 0}}}}
+	ss := newSubjectSnapper(subject, 0.1)
+
+	positions := ss.verticesAlong([]float64{10, 10, 0}, []float64{10, 0, 10})
+	expect(t, len(positions) == 1)
+	expect(t, equalVector(positions[0], []float64{10, 5, 5}))
+
+	// end vertices aren't repeated
+	positions = ss.verticesAlong([]float64{10, 0}, []float64{10, 5})
+	expect(t, len(positions) == 0)
+
+	// nor is a ring's closing vertex
+	positions = ss.verticesAlong([]float64{-1, 0.05}, []float64{1, -0.05})
+	expect(t, len(positions) == 1)
+}
+
+func TestWithSnapToSubject(t *testing.T) {
+	t.Parallel()
+
+	a := Geom{{{{0, 0}, {10, 0}, {10, 5}, {10, 10}, {0, 10}, {0, 0}}}}
+	b := Geom{{{{10.01, 0}, {20, 0}, {20, 10}, {10.01, 10}, {10.01, 0}}}}
+
+	union, err := Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 2)
+
+	p := New(WithSnapToSubject(0.05))
+
+	union, err = p.Union(a, b)
+	terr(t, err)
+	expect(t, len(union) == 1)
+	expect(t, len(union[0]) == 1)
+	expect(t, len(union[0][0]) == 5)
+
+	diff, err := p.Difference(a, b)
+	terr(t, err)
+	eq, err := EqualTopo(diff, a, 0)
+	terr(t, err)
+	expect(t, eq)
+}
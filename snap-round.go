@@ -0,0 +1,208 @@
+package polygol
+
+import (
+	"math"
+	"sort"
+)
+
+// maxSnapRounds bounds how many times the sweep is rerun to route edges
+// through the hot pixels of intersections found between snapped edges.
+// Snapped edges still crossing off the grid after that are an error.
+var maxSnapRounds = 8
+
+// hotPixels are the grid cells containing an input vertex or an
+// intersection point, used for snap rounding.
+type hotPixels struct {
+	size    float64
+	centers [][]float64 // sorted by x, then y
+	seen    map[[2]float64]bool
+}
+
+// findHotPixels nodes the inputs with a sweep, then collects the grid cells
+// of every segment endpoint: input vertices and intersection points alike.
+func (o *operation) findHotPixels(geom Geom, moreGeoms []Geom) (*hotPixels, error) {
+	noder := newOperation("union")
+	noder.opts.robust = o.opts.robust
+	noder.opts.exact = o.opts.exact
+	noder.opts.maxMemory = o.opts.maxMemory
+	mark := o.memoryMark()
+	noder.scope = o.scope
+	segments, _, err := noder.sweepSegments(geom, moreGeoms...)
+	// only the hot pixels found are kept, not counted as the noder's
+	o.forgetMemory(mark)
+	if err != nil {
+		return nil, err
+	}
+
+	hp := &hotPixels{
+		size:    o.opts.precision,
+		centers: [][]float64{},
+		seen:    map[[2]float64]bool{},
+	}
+	hp.add(segments)
+	return hp, nil
+}
+
+// add makes the grid cells of the segment endpoints hot, reporting whether
+// any of them wasn't already.
+func (hp *hotPixels) add(segments []*segment) bool {
+	added := false
+	for i := 0; i < len(segments); i++ {
+		for _, pt := range []*point{segments[i].leftSE.point, segments[i].rightSE.point} {
+			center := [2]float64{hp.snap(pt.x), hp.snap(pt.y)}
+			if hp.seen[center] {
+				continue
+			}
+			hp.seen[center] = true
+			hp.centers = append(hp.centers, []float64{center[0], center[1]})
+			added = true
+		}
+	}
+	if added {
+		sort.Slice(hp.centers, func(i, j int) bool {
+			return comparePositions(hp.centers[i], hp.centers[j]) < 0
+		})
+	}
+	return added
+}
+
+// snap rounds a coordinate to the center of its grid cell. Cells include
+// their lower bounds but not their upper ones, so halves round up.
+func (hp *hotPixels) snap(coord float64) float64 {
+	return math.Floor(coord/hp.size+0.5) * hp.size
+}
+
+// along finds the hot pixels the edge from one position to another passes
+// through, other than those of the positions themselves. It returns their
+// centers ordered from start to end, with any Z/M values interpolated.
+func (hp *hotPixels) along(from, to []float64) [][]float64 {
+	half := hp.size / 2
+	minX, maxX := math.Min(from[0], to[0])-half, math.Max(from[0], to[0])+half
+	minY, maxY := math.Min(from[1], to[1])-half, math.Max(from[1], to[1])+half
+	fromX, fromY := hp.snap(from[0]), hp.snap(from[1])
+	toX, toY := hp.snap(to[0]), hp.snap(to[1])
+
+	v := positionVector(to).sub(positionVector(from))
+	lenSq := dotProduct(v, v)
+
+	type hit struct {
+		t        float64
+		position []float64
+	}
+	hits := []hit{}
+
+	i := sort.Search(len(hp.centers), func(i int) bool {
+		return hp.centers[i][0] >= minX
+	})
+	for ; i < len(hp.centers) && hp.centers[i][0] <= maxX; i++ {
+		c := hp.centers[i]
+		if c[1] < minY || c[1] > maxY {
+			continue
+		}
+		if (c[0] == fromX && c[1] == fromY) || (c[0] == toX && c[1] == toY) {
+			continue
+		}
+		if !segmentIntersectsBox(from, to, c[0]-half, c[1]-half, c[0]+half, c[1]+half) {
+			continue
+		}
+		t := 0.0
+		if lenSq > 0 {
+			t = dotProduct(positionVector(c).sub(positionVector(from)), v) / lenSq
+		}
+		position := []float64{c[0], c[1]}
+		for k := 2; k < len(from) && k < len(to); k++ {
+			position = append(position, from[k]+t*(to[k]-from[k]))
+		}
+		hits = append(hits, hit{t: t, position: position})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].t < hits[j].t
+	})
+	positions := make([][]float64, len(hits))
+	for i := 0; i < len(hits); i++ {
+		positions[i] = hits[i].position
+	}
+	return positions
+}
+
+// route finds the hot pixels the edge from one position to another gets
+// snapped through. Those it passes through come first, as found by along,
+// then those that the snapped pieces between them pass through in turn,
+// since bending the edge moves its pieces into cells the edge missed. The
+// centers are ordered from start to end, leaving out those of the ends.
+func (hp *hotPixels) route(from, to []float64) [][]float64 {
+	path := [][]float64{hp.snapPosition(from)}
+	path = append(path, hp.along(from, to)...)
+	path = append(path, hp.snapPosition(to))
+	for i := 0; i < len(path)-1; {
+		extra := [][]float64{}
+		positions := hp.along(path[i], path[i+1])
+		for j := 0; j < len(positions); j++ {
+			if !onPath(path, positions[j]) {
+				extra = append(extra, positions[j])
+			}
+		}
+		if len(extra) == 0 {
+			i++
+			continue
+		}
+		path = append(path[:i+1], append(extra, path[i+1:]...)...)
+	}
+	return path[1 : len(path)-1]
+}
+
+// snapPosition snaps the X/Y of a position, keeping any Z/M values.
+func (hp *hotPixels) snapPosition(position []float64) []float64 {
+	snapped := append([]float64{}, position...)
+	snapped[0], snapped[1] = hp.snap(position[0]), hp.snap(position[1])
+	return snapped
+}
+
+// onPath tells whether the path already goes through a hot pixel center.
+func onPath(path [][]float64, center []float64) bool {
+	for i := 0; i < len(path); i++ {
+		if path[i][0] == center[0] && path[i][1] == center[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentIntersectsBox clips the segment against the box (Liang-Barsky)
+// to see whether any of it lies within. Like grid cells, the box includes
+// its lower bounds but not its upper ones.
+func segmentIntersectsBox(from, to []float64, minX, minY, maxX, maxY float64) bool {
+	dx := to[0] - from[0]
+	dy := to[1] - from[1]
+	t0, t1 := 0.0, 1.0
+	open0, open1 := false, false
+	// clip keeps the part of the segment where p*t <= q, or p*t < q if
+	// strict
+	clip := func(p, q float64, strict bool) bool {
+		if p == 0 {
+			return q > 0 || (q == 0 && !strict)
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 || (r == t1 && (strict || open1)) {
+				return false
+			}
+			if r > t0 || (r == t0 && strict) {
+				t0, open0 = r, strict
+			}
+		} else {
+			if r < t0 || (r == t0 && (strict || open0)) {
+				return false
+			}
+			if r < t1 || (r == t1 && strict) {
+				t1, open1 = r, strict
+			}
+		}
+		return true
+	}
+	return clip(-dx, from[0]-minX, false) &&
+		clip(dx, maxX-from[0], true) &&
+		clip(-dy, from[1]-minY, false) &&
+		clip(dy, maxY-from[1], true)
+}
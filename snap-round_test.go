@@ -0,0 +1,255 @@
+package polygol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSnapRoundSegmentIntersectsBox(t *testing.T) {
+	t.Parallel()
+
+	// crossing
+	expect(t, segmentIntersectsBox([]float64{-1, 0.5}, []float64{2, 0.5}, 0, 0, 1, 1))
+
+	// inside
+	expect(t, segmentIntersectsBox([]float64{0.2, 0.2}, []float64{0.8, 0.8}, 0, 0, 1, 1))
+
+	// passing by
+	expect(t, !segmentIntersectsBox([]float64{-1, 2}, []float64{2, 1.5}, 0, 0, 1, 1))
+
+	// stopping short
+	expect(t, !segmentIntersectsBox([]float64{-2, 0.5}, []float64{-1, 0.5}, 0, 0, 1, 1))
+
+	// vertical
+	expect(t, segmentIntersectsBox([]float64{0.5, -1}, []float64{0.5, 2}, 0, 0, 1, 1))
+	expect(t, !segmentIntersectsBox([]float64{1.5, -1}, []float64{1.5, 2}, 0, 0, 1, 1))
+
+	// the lower edges are part of the box, the upper ones aren't
+	expect(t, segmentIntersectsBox([]float64{-1, 0}, []float64{2, 0}, 0, 0, 1, 1))
+	expect(t, !segmentIntersectsBox([]float64{-1, 1}, []float64{2, 1}, 0, 0, 1, 1))
+	expect(t, segmentIntersectsBox([]float64{0, 0}, []float64{-1, -1}, 0, 0, 1, 1))
+	expect(t, !segmentIntersectsBox([]float64{1, 1}, []float64{2, 2}, 0, 0, 1, 1))
+	expect(t, !segmentIntersectsBox([]float64{1, 0.5}, []float64{2, 0.5}, 0, 0, 1, 1))
+}
+
+func TestSnapRoundAlong(t *testing.T) {
+	t.Parallel()
+
+	hp := &hotPixels{
+		size: 1,
+		centers: [][]float64{
+			{0, 0},
+			{3, 0},
+			{5, 1},
+			{7, 3},
+			{10, 0},
+		},
+	}
+
+	positions := hp.along([]float64{0, 0, 0}, []float64{10, 1, 101})
+	expect(t, len(positions) == 2)
+	expect(t, equalVector(positions[0], []float64{3, 0, 30}))
+	expect(t, equalVector(positions[1], []float64{5, 1, 51}))
+
+	// reversed direction
+	positions = hp.along([]float64{10, 1}, []float64{0, 0})
+	expect(t, len(positions) == 2)
+	expect(t, equalVector(positions[0], []float64{5, 1}))
+	expect(t, equalVector(positions[1], []float64{3, 0}))
+
+	// endpoint pixels are left out
+	positions = hp.along([]float64{0, 0}, []float64{10, 0})
+	expect(t, len(positions) == 1)
+	expect(t, equalVector(positions[0], []float64{3, 0}))
+}
+
+func TestSnapRoundRoute(t *testing.T) {
+	t.Parallel()
+
+	hp := &hotPixels{
+		size: 1,
+		centers: [][]float64{
+			{0, 0},
+			{2, 1},
+			{4, 2},
+			{6, 2},
+		},
+	}
+
+	// the edge itself passes by [4, 2], but once bent through [2, 1] it
+	// goes through that cell too
+	expect(t, len(hp.along([]float64{0, 0}, []float64{6, 2})) == 1)
+	positions := hp.route([]float64{0, 0}, []float64{6, 2})
+	expect(t, len(positions) == 2)
+	expect(t, equalVector(positions[0], []float64{2, 1}))
+	expect(t, equalVector(positions[1], []float64{4, 2}))
+}
+
+func TestSnapRoundOperation(t *testing.T) {
+	t.Parallel()
+
+	onGrid := func(geom Geom, size float64) bool {
+		for _, poly := range geom {
+			for _, ring := range poly {
+				for _, pos := range ring {
+					for _, c := range pos[:2] {
+						if math.Abs(c/size-math.Round(c/size)) > 1e-6 {
+							return false
+						}
+					}
+				}
+			}
+		}
+		return true
+	}
+
+	a := Geom{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}
+	b := Geom{{{{0.5, -1}, {1.3, 2}, {-0.2, 0.7}, {0.5, -1}}}}
+
+	for _, op := range []Op{OpUnion, OpIntersection, OpDifference, OpXOR} {
+		exact, err := New().Run(op, a, b)
+		terr(t, err)
+		expect(t, !onGrid(exact.Geom, 1e-3))
+
+		snapped, err := New(WithPrecision(1e-3)).Run(op, a, b)
+		terr(t, err)
+		expect(t, onGrid(snapped.Geom, 1e-3))
+
+		equal, err := EqualTopo(exact.Geom, snapped.Geom, 1e-3)
+		terr(t, err)
+		expect(t, equal)
+	}
+
+	// float noise on input vertices is removed
+	c := Geom{{{{0, 0}, {12.000000000001, 0}, {12, 5.999999999}, {0, 6}, {0, 0}}}}
+	result, err := New(WithPrecision(1e-7)).Union(c)
+	terr(t, err)
+	expect(t, equalMultiPoly(result, Geom{{{{0, 0}, {12, 0}, {12, 6}, {0, 6}, {0, 0}}}}))
+}
+
+func TestSnapRoundValid(t *testing.T) {
+	t.Parallel()
+
+	// snapping moves the crossings of these edges into neighbouring cells
+	triangles := []Geom{
+		{{{{1.25, 7.75}, {5.75, 9.5}, {0, 9.5}, {1.25, 7.75}}}},
+		{{{{7, 0}, {5, 9.25}, {2.75, 6.75}, {7, 0}}}},
+		{{{{1.25, 1.5}, {3.5, 9.25}, {9.5, 4}, {1.25, 1.5}}}},
+	}
+	// edges crossing close to each other and to vertices
+	a := Geom{{{{8.75, 0.5}, {3.75, 0.5}, {5.25, 7.75}, {9.75, 6}, {8.75, 0.5}}}}
+	b := Geom{{{{7, 6.5}, {8, 0.5}, {8, 0}, {4.5, 8.75}, {7, 6.5}}}}
+
+	for _, op := range []Op{OpUnion, OpIntersection, OpDifference, OpXOR} {
+		for _, size := range []float64{1, 0.5} {
+			p := New(WithPrecision(size))
+			result, err := p.Run(op, triangles[0], triangles[1:]...)
+			terr(t, err)
+			if defect := snappedDefect(result.Geom); defect != "" {
+				t.Errorf("%s triangles at %v: %s", op, size, defect)
+			}
+			result, err = p.Run(op, a, b)
+			terr(t, err)
+			if defect := snappedDefect(result.Geom); defect != "" {
+				t.Errorf("%s crossings at %v: %s", op, size, defect)
+			}
+		}
+	}
+}
+
+func TestSnapRoundMaxRounds(t *testing.T) {
+	defer func(rounds int) { maxSnapRounds = rounds }(maxSnapRounds)
+
+	a := Geom{{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}}
+	b := Geom{{{{5, -3}, {13, 5}, {5, 5}, {5, -3}}}}
+	want, err := New(WithPrecision(1)).Union(a, b)
+	terr(t, err)
+
+	// starting out with only the cells of the input vertices hot, the
+	// first sweep finds the crossings in cells that aren't
+	sweep := func() (Geom, error) {
+		o := New(WithPrecision(1)).newOperation("union")
+		o.hotPixels = &hotPixels{size: 1, centers: [][]float64{}, seen: map[[2]float64]bool{}}
+		for _, geom := range []Geom{a, b} {
+			for _, pos := range geom[0][0] {
+				center := [2]float64{pos[0], pos[1]}
+				if !o.hotPixels.seen[center] {
+					o.hotPixels.seen[center] = true
+					o.hotPixels.centers = append(o.hotPixels.centers, pos)
+				}
+			}
+		}
+		sort.Slice(o.hotPixels.centers, func(i, j int) bool {
+			return comparePositions(o.hotPixels.centers[i], o.hotPixels.centers[j]) < 0
+		})
+		ringsOut, _, err := o.sweep(a, b)
+		if err != nil {
+			return nil, err
+		}
+		return o.getGeom(ringsOut, nil), nil
+	}
+
+	t.Run("settled", func(t *testing.T) {
+		maxSnapRounds = 2
+		result, err := sweep()
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result, want))
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		maxSnapRounds = 1
+		_, err := sweep()
+		var algErr *algorithmError
+		expect(t, errors.As(err, &algErr))
+	})
+}
+
+// snappedDefect describes the first thing found wrong with snap rounded
+// output: an edge collapsed to a point, or two edges crossing or running
+// along each other. It returns an empty string if there's none.
+func snappedDefect(geom Geom) string {
+	type edge struct{ a, b vector }
+	edges := []edge{}
+	for i := 0; i < len(geom); i++ {
+		for j := 0; j < len(geom[i]); j++ {
+			ring := geom[i][j]
+			for k := 0; k+1 < len(ring); k++ {
+				e := edge{positionVector(ring[k]), positionVector(ring[k+1])}
+				if e.a == e.b {
+					return fmt.Sprintf("collapsed edge at %v", e.a)
+				}
+				edges = append(edges, e)
+			}
+		}
+	}
+	side := func(a, b, c vector) float64 {
+		return crossProduct(b.sub(a), c.sub(a))
+	}
+	// along tells how far c is along the edge, as a fraction of its length
+	along := func(e edge, c vector) float64 {
+		v := e.b.sub(e.a)
+		return dotProduct(c.sub(e.a), v) / dotProduct(v, v)
+	}
+	for i := 0; i < len(edges); i++ {
+		for j := i + 1; j < len(edges); j++ {
+			e, f := edges[i], edges[j]
+			s1, s2 := side(e.a, e.b, f.a), side(e.a, e.b, f.b)
+			if s1 == 0 && s2 == 0 {
+				t1, t2 := along(e, f.a), along(e, f.b)
+				if math.Max(t1, t2) > 0 && math.Min(t1, t2) < 1 {
+					return fmt.Sprintf("edges %v and %v overlap", e, f)
+				}
+				continue
+			}
+			s3, s4 := side(f.a, f.b, e.a), side(f.a, f.b, e.b)
+			if s1*s2 < 0 && s3*s4 < 0 {
+				return fmt.Sprintf("edges %v and %v cross", e, f)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,256 @@
+package polygol
+
+// splayTree is a typed top-down splay tree, following "An implementation of
+// top-down splaying" by D. Sleator. New sweep events mostly go in near the
+// front of the queue and sweep line lookups stay close to the last segment
+// touched, which splaying handles in close to constant time, and comparing
+// the same pairs as the sweep always has keeps results unchanged when
+// rounding errors make comparisons inconsistent.
+type splayTree[T any] struct {
+	root    *splayNode[T]
+	size    int
+	compare func(a, b T) int
+	nodes   *slab[splayNode[T]] // nil to allocate nodes one by one
+}
+
+type splayNode[T any] struct {
+	item        T
+	left, right *splayNode[T]
+}
+
+func newSplayTree[T any](compare func(a, b T) int) *splayTree[T] {
+	return &splayTree[T]{compare: compare}
+}
+
+func (tr *splayTree[T]) newNode(item T) *splayNode[T] {
+	if tr.nodes == nil {
+		return &splayNode[T]{item: item}
+	}
+	node := tr.nodes.alloc()
+	node.item = item
+	return node
+}
+
+// insert adds an item, allowing duplicates, and returns its node.
+func (tr *splayTree[T]) insert(item T) *splayNode[T] {
+	node := tr.newNode(item)
+	tr.size++
+	if tr.root == nil {
+		tr.root = node
+		return node
+	}
+	t := tr.splay(item, tr.root)
+	if tr.compare(item, t.item) < 0 {
+		node.left = t.left
+		node.right = t
+		t.left = nil
+	} else {
+		node.right = t.right
+		node.left = t
+		t.right = nil
+	}
+	tr.root = node
+	return node
+}
+
+// add adds an item if it isn't there yet, returning the node holding it.
+func (tr *splayTree[T]) add(item T) *splayNode[T] {
+	if tr.root == nil {
+		tr.size++
+		tr.root = tr.newNode(item)
+		return tr.root
+	}
+	t := tr.splay(item, tr.root)
+	cmp := tr.compare(item, t.item)
+	if cmp == 0 {
+		tr.root = t
+		return t
+	}
+	node := tr.newNode(item)
+	if cmp < 0 {
+		node.left = t.left
+		node.right = t
+		t.left = nil
+	} else {
+		node.right = t.right
+		node.left = t
+		t.right = nil
+	}
+	tr.size++
+	tr.root = node
+	return node
+}
+
+// remove removes an item if it's there.
+func (tr *splayTree[T]) remove(item T) {
+	if tr.root == nil {
+		return
+	}
+	t := tr.splay(item, tr.root)
+	if tr.compare(item, t.item) != 0 {
+		tr.root = t
+		return
+	}
+	if t.left == nil {
+		tr.root = t.right
+	} else {
+		tr.root = tr.splay(item, t.left)
+		tr.root.right = t.right
+	}
+	tr.size--
+}
+
+// pop removes and returns the node with the smallest item.
+func (tr *splayTree[T]) pop() *splayNode[T] {
+	n := tr.root
+	if n == nil {
+		return nil
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	tr.root = tr.splay(n.item, tr.root)
+	tr.remove(n.item)
+	return n
+}
+
+func (tr *splayTree[T]) find(item T) *splayNode[T] {
+	if tr.root == nil {
+		return nil
+	}
+	tr.root = tr.splay(item, tr.root)
+	if tr.compare(item, tr.root.item) != 0 {
+		return nil
+	}
+	return tr.root
+}
+
+func (tr *splayTree[T]) next(d *splayNode[T]) *splayNode[T] {
+	if d.right != nil {
+		successor := d.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		return successor
+	}
+	var successor *splayNode[T]
+	root := tr.root
+	for root != nil {
+		cmp := tr.compare(d.item, root.item)
+		if cmp == 0 {
+			break
+		} else if cmp < 0 {
+			successor = root
+			root = root.left
+		} else {
+			root = root.right
+		}
+	}
+	return successor
+}
+
+func (tr *splayTree[T]) prev(d *splayNode[T]) *splayNode[T] {
+	if d.left != nil {
+		predecessor := d.left
+		for predecessor.right != nil {
+			predecessor = predecessor.right
+		}
+		return predecessor
+	}
+	var predecessor *splayNode[T]
+	root := tr.root
+	for root != nil {
+		cmp := tr.compare(d.item, root.item)
+		if cmp == 0 {
+			break
+		} else if cmp < 0 {
+			root = root.left
+		} else {
+			predecessor = root
+			root = root.right
+		}
+	}
+	return predecessor
+}
+
+// around finds the node holding item, if any, and the nodes just before
+// and after it. It doesn't splay, so a tree nobody changes can be searched
+// concurrently.
+func (tr *splayTree[T]) around(item T) (prev, node, next *splayNode[T]) {
+	t := tr.root
+	for t != nil {
+		cmp := tr.compare(item, t.item)
+		if cmp == 0 {
+			node = t
+			break
+		} else if cmp < 0 {
+			next = t
+			t = t.left
+		} else {
+			prev = t
+			t = t.right
+		}
+	}
+	if node != nil && node.left != nil {
+		prev = node.left
+		for prev.right != nil {
+			prev = prev.right
+		}
+	}
+	if node != nil && node.right != nil {
+		next = node.right
+		for next.left != nil {
+			next = next.left
+		}
+	}
+	return prev, node, next
+}
+
+// splay brings the node closest to item to the top of the subtree t,
+// which item doesn't need to be in.
+func (tr *splayTree[T]) splay(item T, t *splayNode[T]) *splayNode[T] {
+	var header splayNode[T]
+	l, r := &header, &header
+	for {
+		cmp := tr.compare(item, t.item)
+		if cmp < 0 {
+			if t.left == nil {
+				break
+			}
+			if tr.compare(item, t.left.item) < 0 {
+				y := t.left // rotate right
+				t.left = y.right
+				y.right = t
+				t = y
+				if t.left == nil {
+					break
+				}
+			}
+			r.left = t // link right
+			r = t
+			t = t.left
+		} else if cmp > 0 {
+			if t.right == nil {
+				break
+			}
+			if tr.compare(item, t.right.item) > 0 {
+				y := t.right // rotate left
+				t.right = y.left
+				y.left = t
+				t = y
+				if t.right == nil {
+					break
+				}
+			}
+			l.right = t // link left
+			l = t
+			t = t.right
+		} else {
+			break
+		}
+	}
+	// assemble
+	l.right, r.left = t.left, t.right
+	t.left, t.right = header.right, header.left
+	return t
+}
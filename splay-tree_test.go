@@ -0,0 +1,85 @@
+package polygol
+
+import "testing"
+
+func TestSplayTree(t *testing.T) {
+	t.Parallel()
+
+	comparator := func(a, b float64) int {
+		if a == b {
+			return 0
+		}
+		if a < b {
+			return -1
+		}
+		return 1
+	}
+
+	// test filling up the tree then emptying it out
+	tree := newSplayTree(comparator)
+	k1 := 4.0
+	k2 := 9.0
+	k3 := 13.0
+	k4 := 44.0
+
+	n1 := tree.insert(k1)
+	n2 := tree.insert(k2)
+	n4 := tree.insert(k4)
+	n3 := tree.insert(k3)
+
+	expect(t, tree.find(k1) == n1)
+	expect(t, tree.find(k2) == n2)
+	expect(t, tree.find(k3) == n3)
+	expect(t, tree.find(k4) == n4)
+
+	expect(t, tree.prev(n1) == nil)
+	expect(t, tree.next(n1).item == k2)
+
+	expect(t, tree.prev(n2).item == k1)
+	expect(t, tree.next(n2).item == k3)
+
+	expect(t, tree.prev(n3).item == k2)
+	expect(t, tree.next(n3).item == k4)
+
+	expect(t, tree.prev(n4).item == k3)
+	expect(t, tree.next(n4) == nil)
+
+	tree.remove(k2)
+	expect(t, tree.find(k2) == nil)
+
+	n1 = tree.find(k1)
+	n3 = tree.find(k3)
+	n4 = tree.find(k4)
+
+	expect(t, tree.prev(n1) == nil)
+	expect(t, tree.next(n1).item == k3)
+
+	expect(t, tree.prev(n3).item == k1)
+	expect(t, tree.next(n3).item == k4)
+
+	expect(t, tree.prev(n4).item == k3)
+	expect(t, tree.next(n4) == nil)
+
+	tree.remove(k4)
+	expect(t, tree.find(k4) == nil)
+
+	n1 = tree.find(k1)
+	n3 = tree.find(k3)
+
+	expect(t, tree.prev(n1) == nil)
+	expect(t, tree.next(n1).item == k3)
+
+	expect(t, tree.prev(n3).item == k1)
+	expect(t, tree.next(n3) == nil)
+
+	tree.remove(k1)
+	expect(t, tree.find(k1) == nil)
+
+	n3 = tree.find(k3)
+
+	expect(t, tree.prev(n3) == nil)
+	expect(t, tree.next(n3) == nil)
+
+	tree.remove(k3)
+	expect(t, tree.find(k3) == nil)
+}
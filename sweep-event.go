@@ -18,7 +18,20 @@ type angles struct {
 }
 
 func newSweepEvent(point *point, isLeft bool) *sweepEvent {
-	se := &sweepEvent{}
+	return initSweepEvent(&sweepEvent{}, point, isLeft)
+}
+
+func (o *operation) newSweepEvent(point *point, isLeft bool) *sweepEvent {
+	a := o.alloc()
+	if point.events == nil {
+		// most points end two segments
+		point.events = a.pointEvents.make(0, 2)
+	}
+	return initSweepEvent(a.events.alloc(), point, isLeft)
+}
+
+// initSweepEvent sets up se at point, linking it with the other events there.
+func initSweepEvent(se *sweepEvent, point *point, isLeft bool) *sweepEvent {
 	if point.events == nil {
 		(*point).events = []*sweepEvent{se}
 	} else {
@@ -31,12 +44,14 @@ func newSweepEvent(point *point, isLeft bool) *sweepEvent {
 }
 
 // Compare orders sweep events in the sweep event queue
-func sweepEventCompare(a, b interface{}) int {
-
-	aSE := a.(*sweepEvent)
-	bSE := b.(*sweepEvent)
+func sweepEventCompare(aSE, bSE *sweepEvent) int {
 
-	ptCmp := sweepEventComparePoints(aSE.point, bSE.point)
+	var ptCmp int
+	if aSE.segment != nil && aSE.segment.op != nil {
+		ptCmp = aSE.segment.op.comparePoints(aSE.point, bSE.point)
+	} else {
+		ptCmp = sweepEventComparePoints(aSE.point, bSE.point, Tolerance{})
+	}
 	if ptCmp != 0 {
 		return ptCmp
 	}
@@ -61,18 +76,22 @@ func sweepEventCompare(a, b interface{}) int {
 	return segmentCompare(aSE.segment, bSE.segment)
 }
 
-func sweepEventComparePoints(aPt, bPt *point) int {
-	cmpX := flpCmp(aPt.x, bPt.x)
+func sweepEventComparePoints(aPt, bPt *point, tol Tolerance) int {
+	cmpX := tol.cmp(aPt.x, bPt.x)
 	if cmpX != 0 {
 		return cmpX
 	}
-	return flpCmp(aPt.y, bPt.y)
+	return tol.cmp(aPt.y, bPt.y)
 }
 
 func (se *sweepEvent) link(other *sweepEvent) error {
 	if other.point == se.point {
 		return errors.New("Tried to link already linked events.")
 	}
+	se.point.addSources(other.point.sources)
+	if se.point.zm == nil {
+		se.point.zm = other.point.zm
+	}
 	otherEvents := other.point.events
 	for i := 0; i < len(otherEvents); i++ {
 		evt := otherEvents[i]
@@ -115,9 +134,10 @@ func (se *sweepEvent) checkForConsuming() {
 	}
 }
 
-func (se *sweepEvent) getAvailableLinkedEvents() []*sweepEvent {
+// getAvailableLinkedEvents appends the linked events that could continue
+// an output ring to events, which may be a buffer reused between calls.
+func (se *sweepEvent) getAvailableLinkedEvents(events []*sweepEvent) []*sweepEvent {
 	// point.events is always of length 2 or greater
-	events := []*sweepEvent{}
 	for i := 0; i < len(se.point.events); i++ {
 		evt := se.point.events[i]
 		isInResult := evt.segment.isInResult()
@@ -131,57 +151,76 @@ func (se *sweepEvent) getAvailableLinkedEvents() []*sweepEvent {
 	return events
 }
 
-func (se *sweepEvent) getLeftMostComparator(baseEvent *sweepEvent) func(a, b *sweepEvent) int {
-	cache := make(map[*sweepEvent]angles)
-	fillCache := func(linkedEvent *sweepEvent) {
-		nextEvent := linkedEvent.otherSE
-		cache[linkedEvent] = angles{
-			sine:   sineOfAngle(se.point.xy(), baseEvent.point.xy(), nextEvent.point.xy()),
-			cosine: cosineOfAngle(se.point.xy(), baseEvent.point.xy(), nextEvent.point.xy()),
-		}
+// getAngles returns the angle between the segment coming in from baseEvent
+// and the one leaving along linkedEvent.
+func (se *sweepEvent) getAngles(baseEvent, linkedEvent *sweepEvent) angles {
+	nextEvent := linkedEvent.otherSE
+	sine := sineOfAngle(se.point.xy(), baseEvent.point.xy(), nextEvent.point.xy())
+	if se.segment != nil && se.segment.op != nil && se.segment.op.opts.robust {
+		sine = robustSine(sine, se.point.xy(), baseEvent.point.xy(), nextEvent.point.xy())
 	}
+	return angles{
+		sine:   sine,
+		cosine: cosineOfAngle(se.point.xy(), baseEvent.point.xy(), nextEvent.point.xy()),
+	}
+}
 
+func (se *sweepEvent) getLeftMostComparator(baseEvent *sweepEvent) func(a, b *sweepEvent) int {
 	return func(a, b *sweepEvent) int {
-		if _, ok := cache[a]; !ok {
-			fillCache(a)
-		}
-		if _, ok := cache[b]; !ok {
-			fillCache(b)
-		}
-		aa := cache[a]
-		bb := cache[b]
+		return compareLeftMost(se.getAngles(baseEvent, a), se.getAngles(baseEvent, b))
+	}
+}
 
-		// both on or above x-axis
-		if aa.sine >= 0 && bb.sine >= 0 {
-			if aa.cosine < bb.cosine {
-				return 1
-			}
-			if aa.cosine > bb.cosine {
-				return -1
-			}
-			return 0
+// sortLeftMost sorts linked events from the left-most turn to the
+// right-most, as getLeftMostComparator would, working out each angle only
+// once. The angles are kept in buf, which is returned for reuse.
+func (se *sweepEvent) sortLeftMost(baseEvent *sweepEvent, events []*sweepEvent, buf []angles) []angles {
+	buf = buf[:0]
+	for i := 0; i < len(events); i++ {
+		buf = append(buf, se.getAngles(baseEvent, events[i]))
+	}
+	// there are only a few events at a junction, so an insertion sort,
+	// which is also stable, does fine
+	for i := 1; i < len(events); i++ {
+		for j := i; j > 0 && compareLeftMost(buf[j], buf[j-1]) < 0; j-- {
+			buf[j], buf[j-1] = buf[j-1], buf[j]
+			events[j], events[j-1] = events[j-1], events[j]
 		}
+	}
+	return buf
+}
 
-		// both below x-axis
-		if aa.sine < 0 && bb.sine < 0 {
-			if aa.cosine < bb.cosine {
-				return -1
-			}
-			if aa.cosine > bb.cosine {
-				return 1
-			}
-			return 0
+func compareLeftMost(aa, bb angles) int {
+	// both on or above x-axis
+	if aa.sine >= 0 && bb.sine >= 0 {
+		if aa.cosine < bb.cosine {
+			return 1
 		}
+		if aa.cosine > bb.cosine {
+			return -1
+		}
+		return 0
+	}
 
-		// one above x-axis, one below
-		if bb.sine < aa.sine {
+	// both below x-axis
+	if aa.sine < 0 && bb.sine < 0 {
+		if aa.cosine < bb.cosine {
 			return -1
 		}
-		if bb.sine > aa.sine {
+		if aa.cosine > bb.cosine {
 			return 1
 		}
 		return 0
 	}
+
+	// one above x-axis, one below
+	if bb.sine < aa.sine {
+		return -1
+	}
+	if bb.sine > aa.sine {
+		return 1
+	}
+	return 0
 }
 
 func equalSweepEvents(a, b []*sweepEvent) bool {
@@ -172,14 +172,14 @@ func TestSweepEventLink(t *testing.T) {
 	seAlreadyProcessed.segment = &segment{forceInResult: true, inResult: true, ringOut: &ringOut{}}
 	seNotInResult := newSweepEvent(p1, false)
 	seNotInResult.segment = &segment{forceInResult: true, inResult: false}
-	expect(t, equalSweepEvents(se1.getAvailableLinkedEvents(), []*sweepEvent{}))
+	expect(t, equalSweepEvents(se1.getAvailableLinkedEvents(nil), []*sweepEvent{}))
 
 	// available linked events show up
 	p1 = &point{x: 0, y: 0}
 	se1 = newSweepEvent(p1, false)
 	seOkay := newSweepEvent(p1, false)
 	seOkay.segment = &segment{forceInResult: true, inResult: true}
-	expect(t, equalSweepEvents(se1.getAvailableLinkedEvents(), []*sweepEvent{seOkay}))
+	expect(t, equalSweepEvents(se1.getAvailableLinkedEvents(nil), []*sweepEvent{seOkay}))
 
 	// link goes both ways
 	p1 = &point{x: 0, y: 0}
@@ -187,8 +187,8 @@ func TestSweepEventLink(t *testing.T) {
 	seOkay2 := newSweepEvent(p1, false)
 	seOkay1.segment = &segment{forceInResult: true, inResult: true}
 	seOkay2.segment = &segment{forceInResult: true, inResult: true}
-	expect(t, equalSweepEvents(seOkay1.getAvailableLinkedEvents(), []*sweepEvent{seOkay2}))
-	expect(t, equalSweepEvents(seOkay2.getAvailableLinkedEvents(), []*sweepEvent{seOkay1}))
+	expect(t, equalSweepEvents(seOkay1.getAvailableLinkedEvents(nil), []*sweepEvent{seOkay2}))
+	expect(t, equalSweepEvents(seOkay2.getAvailableLinkedEvents(nil), []*sweepEvent{seOkay1}))
 }
 
 func TestSweepEventgetLeftMostComparator(t *testing.T) {
@@ -269,3 +269,33 @@ func TestSweepEventgetLeftMostComparator(t *testing.T) {
 	expect(t, comparator(se4, se2) == 1)
 	expect(t, comparator(se4, se3) == 1)
 }
+
+func TestSweepEventSortLeftMost(t *testing.T) {
+	t.Parallel()
+
+	op := newOperation("")
+
+	// after a segment straight to the right
+	prevEvent := newSweepEvent(&point{x: 0, y: 0}, false)
+	event := newSweepEvent(&point{x: 1, y: 0}, false)
+
+	ends := []*point{{x: 1, y: -1}, {x: 0, y: 1}, {x: 2, y: 0}, {x: 0, y: -1}, {x: 1, y: 1}}
+	events := []*sweepEvent{}
+	for i := 0; i < len(ends); i++ {
+		se := newSweepEvent(&point{x: 1, y: 0}, false)
+		op.newSegment(se, newSweepEvent(ends[i], false), nil, nil)
+		events = append(events, se)
+	}
+
+	buf := event.sortLeftMost(prevEvent, events, nil)
+	expect(t, len(buf) == len(events))
+	expected := []*point{{x: 0, y: 1}, {x: 1, y: 1}, {x: 2, y: 0}, {x: 1, y: -1}, {x: 0, y: -1}}
+	for i := 0; i < len(events); i++ {
+		expect(t, events[i].otherSE.point.equal(*expected[i]))
+	}
+
+	comparator := event.getLeftMostComparator(prevEvent)
+	for i := 1; i < len(events); i++ {
+		expect(t, comparator(events[i-1], events[i]) == -1)
+	}
+}
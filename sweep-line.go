@@ -1,11 +1,5 @@
 package polygol
 
-import (
-	"fmt"
-
-	splaytree "github.com/engelsjk/splay-tree"
-)
-
 /**
  * NOTE:  We must be careful not to change any segments while
  *        they are in the SplayTree. AFAIK, there's no way to tell
@@ -18,22 +12,17 @@ import (
  */
 
 type sweepLine struct {
-	tree     *splaytree.SplayTree
-	queue    *splaytree.SplayTree
+	tree     *splayTree[*segment]
+	queue    *splayTree[*sweepEvent]
 	segments []*segment
 }
 
-func newSweepLine(
-	queue *splaytree.SplayTree,
-	comparator func(a, b interface{}) int,
-) *sweepLine {
+func newSweepLine(queue *splayTree[*sweepEvent]) *sweepLine {
 	sl := &sweepLine{}
-	if comparator == nil {
-		comparator = segmentCompare
-	}
 	sl.queue = queue
-	sl.tree = splaytree.New(comparator)
-	sl.segments = []*segment{}
+	sl.tree = newSplayTree(segmentCompare)
+	// each segment has two events in the queue to begin with
+	sl.segments = make([]*segment, 0, queue.size/2)
 	return sl
 }
 
@@ -46,22 +35,22 @@ func (sl *sweepLine) process(event *sweepEvent) ([]*sweepEvent, error) {
 	// clean up our body parts and get out
 	if event.consumedBy != nil {
 		if event.isLeft {
-			sl.queue.Remove(event.otherSE)
+			sl.queue.remove(event.otherSE)
 		} else {
-			sl.tree.Remove(seg)
+			sl.tree.remove(seg)
 		}
 		return newEvents, nil
 	}
 
-	var node *splaytree.Node
+	var node *splayNode[*segment]
 	if event.isLeft {
-		node = sl.tree.Insert(seg)
+		node = sl.tree.insert(seg)
 	} else {
-		node = sl.tree.Find(seg)
+		node = sl.tree.find(seg)
 	}
 
 	if node == nil {
-		return nil, fmt.Errorf(
+		return nil, newAlgorithmError(
 			`Unable to find segment #%d [%f, %f] -> [%f, %f] in SweepLine tree. 
 			Please submit a bug report.`,
 			seg.id,
@@ -77,22 +66,22 @@ func (sl *sweepLine) process(event *sweepEvent) ([]*sweepEvent, error) {
 
 	// skip consumed segments still in tree
 	for prevSeg == nil {
-		prevNode = sl.tree.Prev(prevNode)
+		prevNode = sl.tree.prev(prevNode)
 		if prevNode == nil {
 			prevSeg = nil
 			break
-		} else if prevNode.Item().(*segment).consumedBy == nil {
-			prevSeg = prevNode.Item().(*segment)
+		} else if prevNode.item.consumedBy == nil {
+			prevSeg = prevNode.item
 		}
 	}
 
 	// skip consumed segments still in tree
 	for nextSeg == nil {
-		nextNode = sl.tree.Next(nextNode)
+		nextNode = sl.tree.next(nextNode)
 		if nextNode == nil {
 			break
-		} else if nextNode.Item().(*segment).consumedBy == nil {
-			nextSeg = nextNode.Item().(*segment)
+		} else if nextNode.item.consumedBy == nil {
+			nextSeg = nextNode.item
 		}
 	}
 
@@ -117,7 +106,7 @@ func (sl *sweepLine) process(event *sweepEvent) ([]*sweepEvent, error) {
 			} else if nextSplitter == nil {
 				splitter = prevSplitter
 			} else {
-				cmpSplitters := sweepEventComparePoints(prevSplitter, nextSplitter)
+				cmpSplitters := seg.op.comparePoints(prevSplitter, nextSplitter)
 				splitter = nextSplitter
 				if cmpSplitters <= 0 {
 					splitter = prevSplitter
@@ -126,7 +115,7 @@ func (sl *sweepLine) process(event *sweepEvent) ([]*sweepEvent, error) {
 
 			// Rounding errors can cause changes in ordering,
 			// so remove affected segments and right sweep events before splitting
-			sl.queue.Remove(seg.rightSE)
+			sl.queue.remove(seg.rightSE)
 			newEvents = append(newEvents, seg.rightSE)
 
 			newEventsFromSplit := seg.split(splitter)
@@ -137,7 +126,7 @@ func (sl *sweepLine) process(event *sweepEvent) ([]*sweepEvent, error) {
 			// We found some intersections, so re-do the current event to
 			// make sure sweep line ordering is totally consistent for later
 			// use with the segment 'prev' pointers
-			sl.tree.Remove(seg)
+			sl.tree.remove(seg)
 			newEvents = append(newEvents, event)
 
 		} else {
@@ -163,7 +152,7 @@ func (sl *sweepLine) process(event *sweepEvent) ([]*sweepEvent, error) {
 			}
 		}
 
-		sl.tree.Remove(seg)
+		sl.tree.remove(seg)
 	}
 	return newEvents, nil
 }
@@ -176,17 +165,17 @@ func (sl *sweepLine) splitSafely(segment *segment, point *point) []*sweepEvent {
 	// removeNode() doesn't work, so have re-find the seg
 	// https://github.com/w8r/splay-tree/pull/5
 
-	sl.tree.Remove(segment)
+	sl.tree.remove(segment)
 
 	rightSE := segment.rightSE
-	sl.queue.Remove(rightSE)
+	sl.queue.remove(rightSE)
 
 	newEvents := segment.split(point)
 	newEvents = append(newEvents, rightSE)
 
 	// splitting can trigger consumption
 	if segment.consumedBy == nil {
-		sl.tree.Insert(segment)
+		sl.tree.insert(segment)
 	}
 	return newEvents
 }
@@ -0,0 +1,135 @@
+package polygol
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// unionAllNodeSize is the number of geoms unioned together at each node of
+// the cascade.
+const unionAllNodeSize = 8
+
+// UnionAll unions many geoms by cascading: nearby geoms are grouped (by
+// sort-tile-recursive packing of their bboxes), each group is unioned, then
+// groups of those results, and so on up to a single result. The unions at
+// each level run concurrently on up to workers goroutines, or GOMAXPROCS if
+// workers is zero or less. The result doesn't depend on the worker count.
+func (p *Polygol) UnionAll(geoms []Geom, workers int) (Geom, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	level := strPack(geoms, unionAllNodeSize)
+
+	// size and thinness filters only apply to the final result, so that
+	// pieces that would merge into something bigger aren't dropped early
+	inner := *p
+	inner.opts.minArea = 0
+	inner.opts.minThinness = 0
+
+	for {
+		numNodes := (len(level) + unionAllNodeSize - 1) / unionAllNodeSize
+		if numNodes <= 1 {
+			if len(level) == 0 {
+				return p.Union(Geom{})
+			}
+			return p.Union(level[0], level[1:]...)
+		}
+
+		next := make([]Geom, numNodes)
+		errs := make([]error, numNodes)
+		jobs := make(chan int)
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers && w < numNodes; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					start := i * unionAllNodeSize
+					end := start + unionAllNodeSize
+					if end > len(level) {
+						end = len(level)
+					}
+					next[i], errs[i] = inner.Union(level[start], level[start+1:end]...)
+				}
+			}()
+		}
+		for i := 0; i < numNodes; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		for i := 0; i < len(errs); i++ {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+		}
+		level = next
+	}
+}
+
+func UnionAll(geoms []Geom, workers int) (Geom, error) {
+	return New().UnionAll(geoms, workers)
+}
+
+// strPack orders geoms by sort-tile-recursive packing of their bboxes, so
+// that consecutive runs of nodeSize geoms are close together: the geoms are
+// sorted into vertical slices by the x of their centers, then by y within
+// each slice.
+func strPack(geoms []Geom, nodeSize int) []Geom {
+	type entry struct {
+		geom Geom
+		x, y float64
+	}
+	entries := make([]entry, 0, len(geoms))
+	for i := 0; i < len(geoms); i++ {
+		minX, minY := math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for j := 0; j < len(geoms[i]); j++ {
+			if len(geoms[i][j]) == 0 {
+				continue
+			}
+			// holes are within the exterior ring
+			ring := geoms[i][j][0]
+			for k := 0; k < len(ring); k++ {
+				if len(ring[k]) < 2 {
+					continue
+				}
+				minX, maxX = math.Min(minX, ring[k][0]), math.Max(maxX, ring[k][0])
+				minY, maxY = math.Min(minY, ring[k][1]), math.Max(maxY, ring[k][1])
+			}
+		}
+		if minX > maxX {
+			// empty geoms don't add anything
+			continue
+		}
+		entries = append(entries, entry{geom: geoms[i], x: (minX + maxX) / 2, y: (minY + maxY) / 2})
+	}
+
+	numNodes := int(math.Ceil(float64(len(entries)) / float64(nodeSize)))
+	numSlices := int(math.Ceil(math.Sqrt(float64(numNodes))))
+	sliceSize := nodeSize * int(math.Ceil(float64(numNodes)/float64(numSlices)))
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].x < entries[j].x
+	})
+	for start := 0; start < len(entries); start += sliceSize {
+		end := start + sliceSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		slice := entries[start:end]
+		sort.SliceStable(slice, func(i, j int) bool {
+			return slice[i].y < slice[j].y
+		})
+	}
+
+	packed := make([]Geom, 0, len(entries))
+	for i := 0; i < len(entries); i++ {
+		packed = append(packed, entries[i].geom)
+	}
+	return packed
+}
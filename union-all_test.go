@@ -0,0 +1,62 @@
+package polygol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnionAll(t *testing.T) {
+	t.Parallel()
+
+	// a grid of overlapping squares, with a couple of separate islands
+	geoms := []Geom{}
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			x, y := float64(i)*1.5, float64(j)*1.5
+			geoms = append(geoms, Geom{{{{x, y}, {x + 2, y}, {x + 2, y + 2}, {x, y + 2}, {x, y}}}})
+		}
+	}
+	geoms = append(geoms,
+		Geom{{{{30, 30}, {31, 30}, {31, 31}, {30, 31}, {30, 30}}}},
+		Geom{},
+		Geom{{{{-10, 30}, {-9, 30}, {-9, 31}, {-10, 31}, {-10, 30}}}},
+	)
+
+	expected, err := Union(Geom{}, geoms...)
+	terr(t, err)
+
+	result, err := UnionAll(geoms, 1)
+	terr(t, err)
+	expect(t, len(result) == 3)
+	eq, err := EqualTopo(result, expected, 1e-9)
+	terr(t, err)
+	expect(t, eq)
+
+	for _, workers := range []int{0, 2, 8, 100} {
+		other, err := UnionAll(geoms, workers)
+		terr(t, err)
+		expect(t, reflect.DeepEqual(result, other))
+	}
+
+	result, err = UnionAll(nil, 4)
+	terr(t, err)
+	expect(t, len(result) == 0)
+}
+
+func TestStrPack(t *testing.T) {
+	t.Parallel()
+
+	square := func(x, y float64) Geom {
+		return Geom{{{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}, {x, y}}}}
+	}
+	geoms := []Geom{
+		square(10, 10), square(0, 10), square(10, 0), square(0, 0), {},
+	}
+	packed := strPack(geoms, 2)
+	// two slices of two: the left column bottom up, then the right
+	expect(t, len(packed) == 4)
+	expect(t, reflect.DeepEqual(packed[0], square(0, 0)))
+	expect(t, reflect.DeepEqual(packed[1], square(0, 10)))
+	expect(t, reflect.DeepEqual(packed[2], square(10, 0)))
+	expect(t, reflect.DeepEqual(packed[3], square(10, 10)))
+}
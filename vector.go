@@ -4,22 +4,38 @@ import (
 	"math"
 )
 
-// func Intersection(v1, v2 []float64, pt1, pt2 []float64) []float64 {
-func intersection(v1, v2 []float64, pt1, pt2 []float64) []float64 {
+// vector is a 2D point or direction, passed by value so the math in the
+// hot path doesn't allocate.
+type vector struct {
+	x, y float64
+}
+
+// positionVector returns the X/Y of an input position as a vector.
+func positionVector(position []float64) vector {
+	return vector{position[0], position[1]}
+}
+
+func (v vector) sub(o vector) vector {
+	return vector{v.x - o.x, v.y - o.y}
+}
+
+// intersection returns where the lines through pt1 along v1 and pt2 along
+// v2 cross, and false if they are parallel.
+func intersection(v1, v2 vector, pt1, pt2 vector) (vector, bool) {
 	// take some shortcuts for vertical and horizontal lines
 	// this also ensures we don't calculate an intersection and then discover
 	// it's actually outside the bounding box of the line
-	if v1[0] == 0 {
-		return verticalIntersection(v2, pt2, pt1[0])
+	if v1.x == 0 {
+		return verticalIntersection(v2, pt2, pt1.x)
 	}
-	if v2[0] == 0 {
-		return verticalIntersection(v1, pt1, pt2[0])
+	if v2.x == 0 {
+		return verticalIntersection(v1, pt1, pt2.x)
 	}
-	if v1[1] == 0 {
-		return horizontalIntersection(v2, pt2, pt1[1])
+	if v1.y == 0 {
+		return horizontalIntersection(v2, pt2, pt1.y)
 	}
-	if v2[1] == 0 {
-		return horizontalIntersection(v1, pt1, pt2[1])
+	if v2.y == 0 {
+		return horizontalIntersection(v1, pt1, pt2.y)
 	}
 
 	// General case for non-overlapping segments.
@@ -28,112 +44,142 @@ func intersection(v1, v2 []float64, pt1, pt2 []float64) []float64 {
 
 	kross := crossProduct(v1, v2)
 	if kross == 0 {
-		return nil
+		return vector{}, false
 	}
 
-	ve := []float64{pt2[0] - pt1[0], pt2[1] - pt1[1]}
+	ve := pt2.sub(pt1)
 	d1 := crossProduct(ve, v1) / kross
 	d2 := crossProduct(ve, v2) / kross
 
 	// take the average of the two calculations to minimize rounding error
-	x1, x2 := pt1[0]+d2*v1[0], pt2[0]+d1*v2[0]
-	y1, y2 := pt1[1]+d2*v1[1], pt2[1]+d1*v2[1]
+	x1, x2 := pt1.x+d2*v1.x, pt2.x+d1*v2.x
+	y1, y2 := pt1.y+d2*v1.y, pt2.y+d1*v2.y
 	x := (x1 + x2) / 2.0
 	y := (y1 + y2) / 2.0
-	return []float64{x, y}
+	return vector{x, y}, true
 }
 
-func compareAngles(basePt, endPt1, endPt2 []float64) int {
-	v1 := []float64{endPt1[0] - basePt[0], endPt1[1] - basePt[1]}
-	v2 := []float64{endPt2[0] - basePt[0], endPt2[1] - basePt[1]}
+// compareAngles reports which side of the line from basePt through endPt1
+// endPt2 is on, or 0 if it's on the line within the tolerance. Other than
+// the default, tolerances apply to coordinates, so endPt2 is compared with
+// its projection onto the line rather than the cross product with zero.
+func compareAngles(basePt, endPt1, endPt2 vector, tol Tolerance) int {
+	v1 := endPt1.sub(basePt)
+	v2 := endPt2.sub(basePt)
 	kross := crossProduct(v1, v2)
-	return flpCmp(kross, 0)
+	if tol.kind == toleranceDefault {
+		return flpCmp(kross, 0)
+	}
+	if lenSq := dotProduct(v1, v1); lenSq > 0 {
+		t := dotProduct(v2, v1) / lenSq
+		if tol.equal(endPt2.x, basePt.x+t*v1.x) && tol.equal(endPt2.y, basePt.y+t*v1.y) {
+			return 0
+		}
+	}
+	if kross == 0 {
+		return 0
+	}
+	if kross < 0 {
+		return -1
+	}
+	return 1
 }
 
-func sineOfAngle(pShared, pBase, pAngle []float64) float64 {
-	vBase := []float64{pBase[0] - pShared[0], pBase[1] - pShared[1]}
-	vAngle := []float64{pAngle[0] - pShared[0], pAngle[1] - pShared[1]}
+func sineOfAngle(pShared, pBase, pAngle vector) float64 {
+	vBase := pBase.sub(pShared)
+	vAngle := pAngle.sub(pShared)
 	return crossProduct(vAngle, vBase) / length(vAngle) / length(vBase)
 }
 
-func cosineOfAngle(pShared, pBase, pAngle []float64) float64 {
-	vBase := []float64{pBase[0] - pShared[0], pBase[1] - pShared[1]}
-	vAngle := []float64{pAngle[0] - pShared[0], pAngle[1] - pShared[1]}
+func cosineOfAngle(pShared, pBase, pAngle vector) float64 {
+	vBase := pBase.sub(pShared)
+	vAngle := pAngle.sub(pShared)
 	return dotProduct(vAngle, vBase) / length(vAngle) / length(vBase)
 }
 
-func length(v []float64) float64 {
+func length(v vector) float64 {
 	return math.Sqrt(dotProduct(v, v))
 }
 
-func equal(v1, v2 []float64) bool {
-	return v1[0] == v2[0] && v1[1] == v2[1]
-}
-
-func crossProduct(v1, v2 []float64) float64 {
-	return v1[0]*v2[1] - v1[1]*v2[0]
+func crossProduct(v1, v2 vector) float64 {
+	return v1.x*v2.y - v1.y*v2.x
 }
 
-func dotProduct(v1, v2 []float64) float64 {
-	return v1[0]*v2[0] + v1[1]*v2[1]
+func dotProduct(v1, v2 vector) float64 {
+	return v1.x*v2.x + v1.y*v2.y
 }
 
-func perpendicular(v []float64) []float64 {
-	return []float64{-v[1], v[0]}
+func perpendicular(v vector) vector {
+	return vector{-v.y, v.x}
 }
 
-func horizontalIntersection(v []float64, pt []float64, y float64) []float64 {
-	if v[1] == 0 {
-		return nil
+func horizontalIntersection(v vector, pt vector, y float64) (vector, bool) {
+	if v.y == 0 {
+		return vector{}, false
 	}
-	return []float64{pt[0] + v[0]/v[1]*(y-pt[1]), y}
+	return vector{pt.x + v.x/v.y*(y-pt.y), y}, true
 }
 
-func verticalIntersection(v []float64, pt []float64, x float64) []float64 {
-	if v[0] == 0 {
-		return nil
+func verticalIntersection(v vector, pt vector, x float64) (vector, bool) {
+	if v.x == 0 {
+		return vector{}, false
 	}
-	return []float64{x, pt[1] + v[1]/v[0]*(x-pt[0])}
+	return vector{x, pt.y + v.y/v.x*(x-pt.x)}, true
 }
 
-func closestPoint(ptA1, ptA2, ptB []float64) []float64 {
-	if ptA1[0] == ptA2[0] {
-		return []float64{ptA1[0], ptB[1]} // vertical vector
+func closestPoint(ptA1, ptA2, ptB vector) vector {
+	if ptA1.x == ptA2.x {
+		return vector{ptA1.x, ptB.y} // vertical vector
 	}
-	if ptA1[1] == ptA2[1] {
-		return []float64{ptB[0], ptA1[1]} // horizontal vector
+	if ptA1.y == ptA2.y {
+		return vector{ptB.x, ptA1.y} // horizontal vector
 	}
 
 	// determine which point is further away
 	// we use the further point as our base in the calculation, so that the
 	// vectors are more parallel, providing more accurate dot product
-	v1 := []float64{ptB[0] - ptA1[0], ptB[1] - ptA1[1]}
-	v2 := []float64{ptB[0] - ptA2[0], ptB[1] - ptA2[1]}
-	var vFar, vA []float64
-	var farPt []float64
+	v1 := ptB.sub(ptA1)
+	v2 := ptB.sub(ptA2)
+	var vFar, vA, farPt vector
 	if dotProduct(v1, v1) > dotProduct(v2, v2) {
 		vFar = v1
-		vA = []float64{ptA2[0] - ptA1[0], ptA2[1] - ptA1[1]}
+		vA = ptA2.sub(ptA1)
 		farPt = ptA1
 	} else {
 		vFar = v2
-		vA = []float64{ptA1[0] - ptA2[0], ptA1[1] - ptA2[1]}
+		vA = ptA1.sub(ptA2)
 		farPt = ptA2
 	}
 	// manually test if the current point can be considered to be on the line
 	// If the X coordinate was on the line, would the Y coordinate be as well?
-	xDist := (ptB[0] - farPt[0]) / vA[0]
-	if ptB[1] == farPt[1]+xDist*vA[1] {
+	xDist := (ptB.x - farPt.x) / vA.x
+	if ptB.y == farPt.y+xDist*vA.y {
 		return ptB
 	}
 
 	// If the Y coordinate was on the line, would the X coordinate be as well?
-	yDist := (ptB[1] - farPt[1]) / vA[1]
-	if ptB[0] == farPt[0]+yDist*vA[0] {
+	yDist := (ptB.y - farPt.y) / vA.y
+	if ptB.x == farPt.x+yDist*vA.x {
 		return ptB
 	}
 
 	// current point isn't exactly on line, so return closest point
 	dist := dotProduct(vA, vFar) / dotProduct(vA, vA)
-	return []float64{farPt[0] + dist*vA[0], farPt[1] + dist*vA[1]}
+	return vector{farPt.x + dist*vA.x, farPt.y + dist*vA.y}
+}
+
+func distanceToSegment(pt, segStart, segEnd vector) float64 {
+	v := segEnd.sub(segStart)
+	w := pt.sub(segStart)
+	lenSq := dotProduct(v, v)
+	if lenSq == 0 {
+		return length(w)
+	}
+	t := dotProduct(w, v) / lenSq
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return length(vector{w.x - t*v.x, w.y - t*v.y})
 }
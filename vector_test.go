@@ -8,119 +8,133 @@ import (
 func TestVectorCrossProduct(t *testing.T) {
 	t.Parallel()
 
-	v1 := []float64{1, 2}
-	v2 := []float64{3, 4}
+	v1 := vector{1, 2}
+	v2 := vector{3, 4}
 	expect(t, crossProduct(v1, v2) == -2.0)
 }
 
 func TestVectorDotProduct(t *testing.T) {
 	t.Parallel()
 
-	v1 := []float64{1, 2}
-	v2 := []float64{3, 4}
+	v1 := vector{1, 2}
+	v2 := vector{3, 4}
 	expect(t, dotProduct(v1, v2) == 11.0)
 }
 
 func TestVectorLength(t *testing.T) {
 	t.Parallel()
 
-	var v []float64
+	var v vector
 
 	// horizontal
-	v = []float64{3, 0}
+	v = vector{3, 0}
 	expect(t, length(v) == 3.0)
 
 	// vertical
-	v = []float64{0, -2}
+	v = vector{0, -2}
 	expect(t, length(v) == 2.0)
 
 	// 3-4-5
-	v = []float64{3, 4}
+	v = vector{3, 4}
 	expect(t, length(v) == 5.0)
 }
 
 func TestVectorCompareAngles(t *testing.T) {
 	t.Parallel()
 
-	var pt1, pt2, pt3 []float64
+	var pt1, pt2, pt3 vector
 
 	// colinear
-	pt1 = []float64{1, 1}
-	pt2 = []float64{2, 2}
-	pt3 = []float64{3, 3}
-	expect(t, compareAngles(pt1, pt2, pt3) == 0)
-	expect(t, compareAngles(pt2, pt1, pt3) == 0)
-	expect(t, compareAngles(pt2, pt3, pt1) == 0)
-	expect(t, compareAngles(pt3, pt2, pt1) == 0)
+	pt1 = vector{1, 1}
+	pt2 = vector{2, 2}
+	pt3 = vector{3, 3}
+	expect(t, compareAngles(pt1, pt2, pt3, Tolerance{}) == 0)
+	expect(t, compareAngles(pt2, pt1, pt3, Tolerance{}) == 0)
+	expect(t, compareAngles(pt2, pt3, pt1, Tolerance{}) == 0)
+	expect(t, compareAngles(pt3, pt2, pt1, Tolerance{}) == 0)
 
 	// offset
-	pt1 = []float64{0, 0}
-	pt2 = []float64{1, 1}
-	pt3 = []float64{1, 0}
-	expect(t, compareAngles(pt1, pt2, pt3) == -1)
-	expect(t, compareAngles(pt2, pt1, pt3) == 1)
-	expect(t, compareAngles(pt2, pt3, pt1) == -1)
-	expect(t, compareAngles(pt3, pt2, pt1) == 1)
+	pt1 = vector{0, 0}
+	pt2 = vector{1, 1}
+	pt3 = vector{1, 0}
+	expect(t, compareAngles(pt1, pt2, pt3, Tolerance{}) == -1)
+	expect(t, compareAngles(pt2, pt1, pt3, Tolerance{}) == 1)
+	expect(t, compareAngles(pt2, pt3, pt1, Tolerance{}) == -1)
+	expect(t, compareAngles(pt3, pt2, pt1, Tolerance{}) == 1)
+
+	// off the line by less than the tolerance
+	pt1 = vector{0, 0}
+	pt2 = vector{10, 0}
+	pt3 = vector{5, 0.05}
+	expect(t, compareAngles(pt1, pt2, pt3, Tolerance{}) == 1)
+	expect(t, compareAngles(pt1, pt2, pt3, AbsoluteTolerance(0.1)) == 0)
+	expect(t, compareAngles(pt1, pt2, pt3, AbsoluteTolerance(0.01)) == 1)
+	expect(t, compareAngles(pt2, pt1, pt3, AbsoluteTolerance(0.01)) == -1)
+	pt1 = vector{1e6, 1e6}
+	pt2 = vector{1e6 + 10, 1e6}
+	pt3 = vector{1e6 + 5, 1e6 + 0.5}
+	expect(t, compareAngles(pt1, pt2, pt3, RelativeTolerance(1e-6)) == 0)
+	expect(t, compareAngles(pt1, pt2, pt3, RelativeTolerance(1e-8)) == 1)
 }
 
 func TestVectorSineAndCosineOfAngle(t *testing.T) {
 	t.Parallel()
 
-	var shared, base, angle []float64
+	var shared, base, angle vector
 
 	// parallel
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{1, 0}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{1, 0}
 	expect(t, sineOfAngle(shared, base, angle) == 0.0)
 	expect(t, cosineOfAngle(shared, base, angle) == 1.0)
 
 	// 45 degrees
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{1, -1}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{1, -1}
 	expect(t, almostEqual(sineOfAngle(shared, base, angle), math.Sqrt(2.0)/2.0))
 	expect(t, almostEqual(cosineOfAngle(shared, base, angle), math.Sqrt(2.0)/2.0))
 
 	// 90 degrees
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{0, -1}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{0, -1}
 	expect(t, sineOfAngle(shared, base, angle) == 1)
 	expect(t, cosineOfAngle(shared, base, angle) == 0)
 
 	// 135 degrees
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{-1, -1}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{-1, -1}
 	expect(t, almostEqual(sineOfAngle(shared, base, angle), math.Sqrt(2.0)/2.0))
 	expect(t, almostEqual(cosineOfAngle(shared, base, angle), -math.Sqrt(2.0)/2.0))
 
 	// anti-parallel
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{-1, 0}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{-1, 0}
 	expect(t, sineOfAngle(shared, base, angle) == 0)
 	expect(t, cosineOfAngle(shared, base, angle) == -1)
 
 	// 225 degrees
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{-1, 1}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{-1, 1}
 	expect(t, almostEqual(sineOfAngle(shared, base, angle), -math.Sqrt(2.0)/2.0))
 	expect(t, almostEqual(cosineOfAngle(shared, base, angle), -math.Sqrt(2.0)/2.0))
 
 	// 270 degrees
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{0, 1}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{0, 1}
 	expect(t, sineOfAngle(shared, base, angle) == -1)
 	expect(t, cosineOfAngle(shared, base, angle) == 0)
 
 	// 315 degrees
-	shared = []float64{0, 0}
-	base = []float64{1, 0}
-	angle = []float64{1, 1}
+	shared = vector{0, 0}
+	base = vector{1, 0}
+	angle = vector{1, 1}
 	expect(t, almostEqual(sineOfAngle(shared, base, angle), -math.Sqrt(2.0)/2.0))
 	expect(t, almostEqual(cosineOfAngle(shared, base, angle), math.Sqrt(2.0)/2.0))
 }
@@ -128,28 +142,28 @@ func TestVectorSineAndCosineOfAngle(t *testing.T) {
 func TestVectorPerpindicular(t *testing.T) {
 	t.Parallel()
 
-	var v, r []float64
+	var v, r vector
 
 	// vertical
-	v = []float64{0, 1}
+	v = vector{0, 1}
 	r = perpendicular(v)
 	expect(t, dotProduct(v, r) == 0)
 	expect(t, crossProduct(v, r) != 0)
 
 	// horizontal
-	v = []float64{1, 0}
+	v = vector{1, 0}
 	r = perpendicular(v)
 	expect(t, dotProduct(v, r) == 0)
 	expect(t, crossProduct(v, r) != 0)
 
 	// 45 degrees
-	v = []float64{1, 1}
+	v = vector{1, 1}
 	r = perpendicular(v)
 	expect(t, dotProduct(v, r) == 0)
 	expect(t, crossProduct(v, r) != 0)
 
 	// 120 degrees
-	v = []float64{-1, 2}
+	v = vector{-1, 2}
 	r = perpendicular(v)
 	expect(t, dotProduct(v, r) == 0)
 	expect(t, crossProduct(v, r) != 0)
@@ -158,194 +172,253 @@ func TestVectorPerpindicular(t *testing.T) {
 func TestVectorClosestPoint(t *testing.T) {
 	t.Parallel()
 
-	var pA1, pA2, pB, cp, expected []float64
+	var pA1, pA2, pB, cp, expected vector
 
 	// on line
-	pA1 = []float64{2, 2}
-	pA2 = []float64{3, 3}
-	pB = []float64{-1, -1}
+	pA1 = vector{2, 2}
+	pA2 = vector{3, 3}
+	pB = vector{-1, -1}
 	cp = closestPoint(pA1, pA2, pB)
-	expect(t, equal(cp, pB))
+	expect(t, cp == pB)
 
 	// on first point
-	pA1 = []float64{2, 2}
-	pA2 = []float64{3, 3}
-	pB = []float64{2, 2}
+	pA1 = vector{2, 2}
+	pA2 = vector{3, 3}
+	pB = vector{2, 2}
 	cp = closestPoint(pA1, pA2, pB)
-	expect(t, equal(cp, pB))
+	expect(t, cp == pB)
 
 	// off line above
-	pA1 = []float64{2, 2}
-	pA2 = []float64{3, 1}
-	pB = []float64{3, 7}
-	expected = []float64{0, 4}
-	expect(t, equal(closestPoint(pA1, pA2, pB), expected))
-	expect(t, equal(closestPoint(pA2, pA1, pB), expected))
+	pA1 = vector{2, 2}
+	pA2 = vector{3, 1}
+	pB = vector{3, 7}
+	expected = vector{0, 4}
+	expect(t, closestPoint(pA1, pA2, pB) == expected)
+	expect(t, closestPoint(pA2, pA1, pB) == expected)
 
 	// off line below
-	pA1 = []float64{2, 2}
-	pA2 = []float64{3, 1}
-	pB = []float64{0, 2}
-	expected = []float64{1, 3}
-	expect(t, equal(closestPoint(pA1, pA2, pB), expected))
-	expect(t, equal(closestPoint(pA2, pA1, pB), expected))
+	pA1 = vector{2, 2}
+	pA2 = vector{3, 1}
+	pB = vector{0, 2}
+	expected = vector{1, 3}
+	expect(t, closestPoint(pA1, pA2, pB) == expected)
+	expect(t, closestPoint(pA2, pA1, pB) == expected)
 
 	// off line perpendicular to first point
-	pA1 = []float64{2, 2}
-	pA2 = []float64{3, 3}
-	pB = []float64{1, 3}
+	pA1 = vector{2, 2}
+	pA2 = vector{3, 3}
+	pB = vector{1, 3}
 	cp = closestPoint(pA1, pA2, pB)
-	expected = []float64{2, 2}
-	expect(t, equal(cp, expected))
+	expected = vector{2, 2}
+	expect(t, cp == expected)
 
 	// horizontal vector
-	pA1 = []float64{2, 2}
-	pA2 = []float64{3, 2}
-	pB = []float64{1, 3}
+	pA1 = vector{2, 2}
+	pA2 = vector{3, 2}
+	pB = vector{1, 3}
 	cp = closestPoint(pA1, pA2, pB)
-	expected = []float64{1, 2}
-	expect(t, equal(cp, expected))
+	expected = vector{1, 2}
+	expect(t, cp == expected)
 
 	// vertical vector
-	pA1 = []float64{2, 2}
-	pA2 = []float64{2, 3}
-	pB = []float64{1, 3}
+	pA1 = vector{2, 2}
+	pA2 = vector{2, 3}
+	pB = vector{1, 3}
 	cp = closestPoint(pA1, pA2, pB)
-	expected = []float64{2, 3}
-	expect(t, equal(cp, expected))
+	expected = vector{2, 3}
+	expect(t, cp == expected)
 
 	// on line but dot product does not think so - part of issue 60-2
-	pA1 = []float64{-45.3269382, -1.4059341}
-	pA2 = []float64{-45.326737413921656, -1.40635}
-	pB = []float64{-45.326833968900424, -1.40615}
+	pA1 = vector{-45.3269382, -1.4059341}
+	pA2 = vector{-45.326737413921656, -1.40635}
+	pB = vector{-45.326833968900424, -1.40615}
 	cp = closestPoint(pA1, pA2, pB)
-	expect(t, equal(cp, pB))
+	expect(t, cp == pB)
 }
 
 func TestVectorVerticalIntersection(t *testing.T) {
 	t.Parallel()
 
-	var pt, i, v []float64
+	var pt, i, v vector
 	var x float64
+	var ok bool
 
 	// horizontal
-	pt = []float64{42, 3}
-	v = []float64{-2, 0}
+	pt = vector{42, 3}
+	v = vector{-2, 0}
 	x = 37
-	i = verticalIntersection(v, pt, x)
-	expect(t, i[0] == 37)
-	expect(t, i[1] == 3)
+	i, ok = verticalIntersection(v, pt, x)
+	expect(t, ok)
+	expect(t, i.x == 37)
+	expect(t, i.y == 3)
 
 	// vertical
-	pt = []float64{42, 3}
-	v = []float64{0, 4}
+	pt = vector{42, 3}
+	v = vector{0, 4}
 	x = 37
-	expect(t, verticalIntersection(v, pt, x) == nil)
+	_, ok = verticalIntersection(v, pt, x)
+	expect(t, !ok)
 
 	// 45 degree
-	pt = []float64{1, 1}
-	v = []float64{1, 1}
+	pt = vector{1, 1}
+	v = vector{1, 1}
 	x = -2
-	i = verticalIntersection(v, pt, x)
-	expect(t, i[0] == -2)
-	expect(t, i[1] == -2)
+	i, ok = verticalIntersection(v, pt, x)
+	expect(t, ok)
+	expect(t, i.x == -2)
+	expect(t, i.y == -2)
 
 	// upper left quadrant
-	pt = []float64{-1, 1}
-	v = []float64{-2, 1}
+	pt = vector{-1, 1}
+	v = vector{-2, 1}
 	x = -3
-	i = verticalIntersection(v, pt, x)
-	expect(t, i[0] == -3)
-	expect(t, i[1] == 2)
+	i, ok = verticalIntersection(v, pt, x)
+	expect(t, ok)
+	expect(t, i.x == -3)
+	expect(t, i.y == 2)
 }
 
 func TestVectorHorizontalIntersection(t *testing.T) {
 	t.Parallel()
 
-	var pt, i, v []float64
+	var pt, i, v vector
 	var y float64
+	var ok bool
 
 	// horizontal
-	pt = []float64{42, 3}
-	v = []float64{-2, 0}
+	pt = vector{42, 3}
+	v = vector{-2, 0}
 	y = 37
-	expect(t, horizontalIntersection(v, pt, y) == nil)
+	_, ok = horizontalIntersection(v, pt, y)
+	expect(t, !ok)
 
 	// vertical
-	pt = []float64{42, 3}
-	v = []float64{0, 4}
+	pt = vector{42, 3}
+	v = vector{0, 4}
 	y = 37
-	i = horizontalIntersection(v, pt, y)
-	expect(t, i[0] == 42)
-	expect(t, i[1] == 37)
+	i, ok = horizontalIntersection(v, pt, y)
+	expect(t, ok)
+	expect(t, i.x == 42)
+	expect(t, i.y == 37)
 
 	// 45 degree
-	pt = []float64{1, 1}
-	v = []float64{1, 1}
+	pt = vector{1, 1}
+	v = vector{1, 1}
 	y = 4
-	i = horizontalIntersection(v, pt, y)
-	expect(t, i[0] == 4)
-	expect(t, i[1] == 4)
+	i, ok = horizontalIntersection(v, pt, y)
+	expect(t, ok)
+	expect(t, i.x == 4)
+	expect(t, i.y == 4)
 
 	// bottom left quadrant
-	pt = []float64{-1, -1}
-	v = []float64{-2, -1}
+	pt = vector{-1, -1}
+	v = vector{-2, -1}
 	y = -3
-	i = horizontalIntersection(v, pt, y)
-	expect(t, i[0] == -5)
-	expect(t, i[1] == -3)
+	i, ok = horizontalIntersection(v, pt, y)
+	expect(t, ok)
+	expect(t, i.x == -5)
+	expect(t, i.y == -3)
 }
 
 func TestVectorIntersection(t *testing.T) {
 	t.Parallel()
 
-	var i, v1, v2 []float64
+	var i, v1, v2 vector
+	var ok bool
 
-	p1 := []float64{42, 42}
-	p2 := []float64{-32, 46}
+	p1 := vector{42, 42}
+	p2 := vector{-32, 46}
 
 	// parallel
-	v1 = []float64{1, 2}
-	v2 = []float64{-1, -2}
-	i = intersection(v1, v2, p1, p2)
-	expect(t, i == nil)
+	v1 = vector{1, 2}
+	v2 = vector{-1, -2}
+	_, ok = intersection(v1, v2, p1, p2)
+	expect(t, !ok)
 
 	// horizontal and vertical
-	v1 = []float64{0, 2}
-	v2 = []float64{-1, 0}
-	i = intersection(v1, v2, p1, p2)
-	expect(t, i[0] == 42)
-	expect(t, i[1] == 46)
+	v1 = vector{0, 2}
+	v2 = vector{-1, 0}
+	i, ok = intersection(v1, v2, p1, p2)
+	expect(t, ok)
+	expect(t, i.x == 42)
+	expect(t, i.y == 46)
 
 	// horizontal
-	v1 = []float64{1, 1}
-	v2 = []float64{-1, 0}
-	i = intersection(v1, v2, p1, p2)
-	expect(t, i[0] == 46)
-	expect(t, i[1] == 46)
+	v1 = vector{1, 1}
+	v2 = vector{-1, 0}
+	i, ok = intersection(v1, v2, p1, p2)
+	expect(t, ok)
+	expect(t, i.x == 46)
+	expect(t, i.y == 46)
 
 	// vertical
-	v1 = []float64{1, 1}
-	v2 = []float64{0, 1}
-	i = intersection(v1, v2, p1, p2)
-	expect(t, i[0] == -32)
-	expect(t, i[1] == -32)
+	v1 = vector{1, 1}
+	v2 = vector{0, 1}
+	i, ok = intersection(v1, v2, p1, p2)
+	expect(t, ok)
+	expect(t, i.x == -32)
+	expect(t, i.y == -32)
 
 	// 45 degree && 135 degree
-	v1 = []float64{1, 1}
-	v2 = []float64{-1, 1}
-	i = intersection(v1, v2, p1, p2)
-	expect(t, i[0] == 7)
-	expect(t, i[1] == 7)
+	v1 = vector{1, 1}
+	v2 = vector{-1, 1}
+	i, ok = intersection(v1, v2, p1, p2)
+	expect(t, ok)
+	expect(t, i.x == 7)
+	expect(t, i.y == 7)
 
 	// consistency
 	// Taken from https://github.com/mfogel/polygon-clipping/issues/37
-	p1 = []float64{0.523787, 51.281453}
-	v1 = []float64{0.0002729999999999677, 0.0002729999999999677}
-	p2 = []float64{0.523985, 51.281651}
-	v2 = []float64{0.000024999999999941735, 0.000049000000004184585}
-	i1 := intersection(v1, v2, p1, p2)
-	i2 := intersection(v2, v1, p2, p1)
-	expect(t, i1[0] == i2[0])
-	expect(t, i1[1] == i2[1])
+	p1 = vector{0.523787, 51.281453}
+	v1 = vector{0.0002729999999999677, 0.0002729999999999677}
+	p2 = vector{0.523985, 51.281651}
+	v2 = vector{0.000024999999999941735, 0.000049000000004184585}
+	i1, _ := intersection(v1, v2, p1, p2)
+	i2, _ := intersection(v2, v1, p2, p1)
+	expect(t, i1.x == i2.x)
+	expect(t, i1.y == i2.y)
+}
+
+func TestVectorDistanceToSegment(t *testing.T) {
+	t.Parallel()
+
+	a := vector{0, 0}
+	b := vector{4, 0}
+
+	// beside the segment
+	expect(t, distanceToSegment(vector{2, 3}, a, b) == 3.0)
+
+	// on the segment
+	expect(t, distanceToSegment(vector{1, 0}, a, b) == 0.0)
+
+	// past an endpoint
+	expect(t, distanceToSegment(vector{7, 4}, a, b) == 5.0)
+
+	// degenerate segment
+	expect(t, distanceToSegment(vector{3, 4}, a, a) == 5.0)
+}
+
+func TestVectorAllocs(t *testing.T) {
+	// not parallel, allocations are counted across the whole program
+
+	a, b, c := vector{0, 0}, vector{4, 1}, vector{1, 3}
+	allocs := testing.AllocsPerRun(100, func() {
+		intersection(b.sub(a), c.sub(a), a, vector{2, -1})
+		compareAngles(a, b, c, Tolerance{})
+		sineOfAngle(a, b, c)
+		cosineOfAngle(a, b, c)
+		closestPoint(a, b, c)
+		distanceToSegment(c, a, b)
+		orient2d(a, b, c)
+	})
+	expect(t, allocs == 0)
+}
+
+func BenchmarkVectorIntersection(b *testing.B) {
+	v1, v2 := vector{0.0002729999999999677, 0.0002729999999999677}, vector{0.000024999999999941735, 0.000049000000004184585}
+	p1, p2 := vector{0.523787, 51.281453}, vector{0.523985, 51.281651}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		intersection(v1, v2, p1, p2)
+	}
 }